@@ -0,0 +1,173 @@
+package ahocorasick
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/gods/sets"
+	"unicode/utf8"
+)
+
+// Automaton is an Aho-Corasick automaton for a set of keywords.
+type Automaton struct {
+	words   []string
+	lengths []int // Length of each keyword in runes
+	maxLen  int   // Length of the longest keyword in runes
+	d       dfa.TaggedDfa
+}
+
+// Match is an occurrence of a keyword in a string.
+type Match struct {
+	Keyword int // Index of the keyword
+	Start   int // Byte offset of the start of the occurrence
+	End     int // Byte offset just past the end of the occurrence
+}
+
+// New builds an Aho-Corasick automaton for the provided keywords.
+// The trie built by dfa.FromKeywords serves as the goto function,
+// and a breadth-first traversal of it computes the failure link of
+// each state, which is the state for the longest proper suffix of its
+// string which is also a prefix of a keyword. The failure links are
+// then folded into the transition function, so that each letter of
+// a string being searched causes exactly one transition.
+func New(words []string) *Automaton {
+	d := dfa.FromKeywords(words)
+	alphabet := d.S.Elements()
+
+	out := make([]sets.SetInt, d.Q)
+	for q := range out {
+		out[q] = sets.NewSetInt()
+	}
+	for i, word := range words {
+		state := d.Qs
+		for _, letter := range word {
+			state = d.D[state][letter]
+		}
+		out[state].Insert(i)
+	}
+
+	fail := make([]int, d.Q)
+	queue := []int{}
+	for _, letter := range alphabet {
+		if child, ok := d.D[d.Qs][letter]; ok {
+			fail[child] = d.Qs
+			out[child].Merge(out[d.Qs])
+			queue = append(queue, child)
+		} else {
+			d.D[d.Qs][letter] = d.Qs
+		}
+	}
+
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		for _, letter := range alphabet {
+			if child, ok := d.D[state][letter]; ok {
+				fail[child] = d.D[fail[state]][letter]
+				out[child].Merge(out[fail[child]])
+				queue = append(queue, child)
+			} else {
+				d.D[state][letter] = d.D[fail[state]][letter]
+			}
+		}
+	}
+
+	d.F = sets.NewSetInt()
+	for q := range out {
+		if !out[q].IsEmpty() {
+			d.F.Insert(q)
+		}
+	}
+
+	a := &Automaton{
+		words:   append([]string{}, words...),
+		lengths: make([]int, len(words)),
+		d:       dfa.TaggedDfa{Dfa: d, T: out},
+	}
+	for i, word := range words {
+		a.lengths[i] = utf8.RuneCountInString(word)
+		if a.lengths[i] > a.maxLen {
+			a.maxLen = a.lengths[i]
+		}
+	}
+	return a
+}
+
+// FindAll returns all occurrences of the keywords in s, including
+// overlapping ones, ordered by their end offsets and then by the
+// indices of their keywords. A return value of nil indicates no
+// match. Each invalid byte in s is read as utf8.RuneError, and so
+// matches a U+FFFD in a keyword.
+func (a *Automaton) FindAll(s string) []Match {
+	var result []Match
+	state := a.d.Qs
+
+	// offsets[count%len(offsets)] is the byte offset after the first
+	// count runes, kept for as many runes as the longest keyword has,
+	// since the bytes of s and of a keyword need not have the same
+	// length when s is not valid UTF-8.
+	offsets := make([]int, a.maxLen+1)
+	count := 0
+
+	result = a.appendMatches(result, state, offsets, count)
+	for n := 0; n < len(s); {
+		letter, size := utf8.DecodeRuneInString(s[n:])
+		n += size
+		state = a.next(state, letter)
+		count++
+		offsets[count%len(offsets)] = n
+		result = a.appendMatches(result, state, offsets, count)
+	}
+
+	return result
+}
+
+// Contains returns true if any of the keywords occurs in s.
+func (a *Automaton) Contains(s string) bool {
+	state := a.d.Qs
+	if a.d.F.Contains(state) {
+		return true
+	}
+	for _, letter := range s {
+		state = a.next(state, letter)
+		if a.d.F.Contains(state) {
+			return true
+		}
+	}
+	return false
+}
+
+// Keywords returns the keywords, in the order in which they were
+// provided to New.
+func (a *Automaton) Keywords() []string {
+	return append([]string{}, a.words...)
+}
+
+// Dfa returns the automaton as a tagged deterministic finite
+// automaton, with the failure links folded into its transition
+// function. It accepts exactly those strings over the alphabet of the
+// keywords which end with a keyword, and each of its states is tagged
+// with the indices of the keywords which end there. Runes outside the
+// alphabet have no transitions, although FindAll and Contains treat
+// them as returning to the start state.
+func (a *Automaton) Dfa() dfa.TaggedDfa {
+	return a.d
+}
+
+// next returns the state moved to from state on letter.
+func (a *Automaton) next(state int, letter rune) int {
+	if next, ok := a.d.D[state][letter]; ok {
+		return next
+	}
+	return a.d.Qs
+}
+
+// appendMatches appends to result the matches of the keywords ending
+// in state after count runes, finding their byte offsets in offsets
+// as described for FindAll.
+func (a *Automaton) appendMatches(result []Match, state int, offsets []int, count int) []Match {
+	end := offsets[count%len(offsets)]
+	for _, i := range a.d.T[state].Elements() {
+		start := offsets[(count-a.lengths[i])%len(offsets)]
+		result = append(result, Match{i, start, end})
+	}
+	return result
+}
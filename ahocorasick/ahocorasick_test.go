@@ -0,0 +1,147 @@
+package ahocorasick_test
+
+import (
+	"github.com/paulgriffiths/automata/ahocorasick"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// naiveFindAll finds all occurrences of the keywords in s by trying
+// each keyword at each offset.
+func naiveFindAll(words []string, s string) []ahocorasick.Match {
+	var result []ahocorasick.Match
+	for end := 0; end <= len(s); end++ {
+		for i, word := range words {
+			if strings.HasSuffix(s[:end], word) {
+				result = append(result, ahocorasick.Match{
+					Keyword: i,
+					Start:   end - len(word),
+					End:     end,
+				})
+			}
+		}
+	}
+	return result
+}
+
+func TestFindAll(t *testing.T) {
+	testCases := []struct {
+		words []string
+		input string
+		want  []ahocorasick.Match
+	}{
+		{
+			[]string{"he", "she", "his", "hers"},
+			"ushers",
+			[]ahocorasick.Match{{0, 2, 4}, {1, 1, 4}, {3, 2, 6}},
+		},
+		{
+			[]string{"a", "aa", "aa"},
+			"aaa",
+			[]ahocorasick.Match{
+				{0, 0, 1}, {0, 1, 2}, {1, 0, 2}, {2, 0, 2},
+				{0, 2, 3}, {1, 1, 3}, {2, 1, 3},
+			},
+		},
+		{[]string{"abc"}, "ab-abc", []ahocorasick.Match{{0, 3, 6}}},
+		{[]string{"abc"}, "abab", nil},
+		{[]string{}, "abc", nil},
+		{[]string{""}, "ab", []ahocorasick.Match{{0, 0, 0}, {0, 1, 1}, {0, 2, 2}}},
+		{[]string{"ñu", "u"}, "€ñu", []ahocorasick.Match{{0, 3, 6}, {1, 5, 6}}},
+		{[]string{"\uFFFD"}, "\xff", []ahocorasick.Match{{0, 0, 1}}},
+		{[]string{"a\uFFFDb"}, "xa\xff\xfeab", nil},
+		{[]string{"\uFFFDa", "a"}, "\xe2\x82a", []ahocorasick.Match{{0, 1, 3}, {1, 2, 3}}},
+	}
+
+	for n, tc := range testCases {
+		a := ahocorasick.New(tc.words)
+		if got := a.FindAll(tc.input); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("case %d, got %v, want %v", n+1, got, tc.want)
+		}
+		if got, want := a.Contains(tc.input), tc.want != nil; got != want {
+			t.Errorf("case %d, got %t, want %t", n+1, got, want)
+		}
+	}
+}
+
+func TestFindAllRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	randomString := func(alphabet string, max int) string {
+		b := make([]byte, r.Intn(max+1))
+		for i := range b {
+			b[i] = alphabet[r.Intn(len(alphabet))]
+		}
+		return string(b)
+	}
+
+	for n := 0; n < 200; n++ {
+		words := make([]string, 1+r.Intn(8))
+		for i := range words {
+			words[i] = randomString("abc", 4)
+		}
+		a := ahocorasick.New(words)
+		for i := 0; i < 10; i++ {
+			s := randomString("abcd", 20)
+			got, want := a.FindAll(s), naiveFindAll(words, s)
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("keywords %q, string %q, got %v, want %v",
+					words, s, got, want)
+			}
+		}
+	}
+}
+
+func TestDfa(t *testing.T) {
+	words := []string{"he", "she", "his", "hers"}
+	a := ahocorasick.New(words)
+	d := a.Dfa()
+
+	if err := d.Validate(); err != nil {
+		t.Fatalf("invalid automaton: %v", err)
+	}
+
+	m := d.Minimize()
+	for _, s := range allStrings("ehirs", 5) {
+		want := naiveFindAll(words, s)
+		var ending []int
+		for _, match := range want {
+			if match.End == len(s) {
+				ending = append(ending, match.Keyword)
+			}
+		}
+
+		if got := d.Tags(s); !reflect.DeepEqual(got, ending) {
+			t.Errorf("string %q, got tags %v, want %v", s, got, ending)
+		}
+		if got := m.Tags(s); !reflect.DeepEqual(got, ending) {
+			t.Errorf("string %q, got minimized tags %v, want %v", s, got, ending)
+		}
+		if got := d.Accepts(s); got != (ending != nil) {
+			t.Errorf("string %q, got %t, want %t", s, got, ending != nil)
+		}
+	}
+
+	if got := a.Keywords(); !reflect.DeepEqual(got, words) {
+		t.Errorf("got keywords %q, want %q", got, words)
+	}
+}
+
+// allStrings returns all the strings over the provided alphabet with
+// length at most n.
+func allStrings(alphabet string, n int) []string {
+	result := []string{""}
+	current := []string{""}
+	for i := 0; i < n; i++ {
+		next := []string{}
+		for _, s := range current {
+			for _, letter := range alphabet {
+				next = append(next, s+string(letter))
+			}
+		}
+		result = append(result, next...)
+		current = next
+	}
+	return result
+}
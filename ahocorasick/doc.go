@@ -0,0 +1,6 @@
+/*
+Package ahocorasick implements the Aho-Corasick string matching
+algorithm, which finds all occurrences of a set of keywords in a
+string in a single pass.
+*/
+package ahocorasick
@@ -0,0 +1,18 @@
+package ahocorasick_test
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/automata/ahocorasick"
+)
+
+func ExampleAutomaton_FindAll() {
+	a := ahocorasick.New([]string{"he", "she", "his", "hers"})
+	for _, m := range a.FindAll("ushers") {
+		fmt.Println(m.Keyword, m.Start, m.End)
+	}
+
+	// Output:
+	// 0 2 4
+	// 1 1 4
+	// 3 2 6
+}
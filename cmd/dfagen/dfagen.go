@@ -0,0 +1,81 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/automata/regex"
+	"io/ioutil"
+	"os"
+	"strconv"
+)
+
+func main() {
+	pkg := flag.String("package", "main", "package name for generated code")
+	fn := flag.String("func", "Match", "name of generated function")
+	jsonFile := flag.String("json", "", "read DFA from JSON `file` instead of pattern")
+	out := flag.String("o", "", "write generated code to `file` instead of stdout")
+	test := flag.String("test", "", "also write a generated test to `file`")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: dfagen [flags] pattern\n")
+		fmt.Fprintf(os.Stderr, "       dfagen [flags] -json file\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var d dfa.Dfa
+	var description string
+
+	switch {
+	case *jsonFile != "" && flag.NArg() == 0:
+		data, err := ioutil.ReadFile(*jsonFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "dfagen: couldn't read file: %v\n", err)
+			os.Exit(1)
+		}
+		if err := json.Unmarshal(data, &d); err != nil {
+			fmt.Fprintf(os.Stderr, "dfagen: invalid automaton: %v\n", err)
+			os.Exit(1)
+		}
+		description = "the automaton in " + *jsonFile
+	case *jsonFile == "" && flag.NArg() == 1:
+		rex := regex.CompileWithOptions(flag.Arg(0),
+			regex.Options{Backend: regex.Derivatives})
+		if rex == nil {
+			fmt.Fprintf(os.Stderr, "dfagen: invalid regular expression\n")
+			os.Exit(1)
+		}
+		d = rex.Dfa()
+		description = "the regular expression " + strconv.Quote(flag.Arg(0))
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	g := newGenerator(d, *pkg, *fn, description)
+
+	src, err := g.source()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "dfagen: couldn't generate code: %v\n", err)
+		os.Exit(1)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+	} else if err := ioutil.WriteFile(*out, src, 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "dfagen: couldn't write file: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *test != "" {
+		src, err := g.test()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "dfagen: couldn't generate test: %v\n", err)
+			os.Exit(1)
+		}
+		if err := ioutil.WriteFile(*test, src, 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "dfagen: couldn't write file: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
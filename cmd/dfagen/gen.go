@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/paulgriffiths/automata/dfa"
+	"go/format"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// generator writes Go source for a matcher implementing a Dfa.
+type generator struct {
+	d           dfa.Dfa
+	pkg, fn     string
+	description string
+	alphabet    []rune
+}
+
+// newGenerator returns a generator for the minimized form of the
+// provided Dfa. The description is used in comments to identify
+// what the generated function matches.
+func newGenerator(d dfa.Dfa, pkg, fn, description string) *generator {
+	d = d.Minimize()
+	alphabet := d.S.Elements()
+	for _, trans := range d.D {
+		for letter := range trans {
+			if !d.S.Contains(letter) {
+				alphabet = append(alphabet, letter)
+			}
+		}
+	}
+	sort.Slice(alphabet, func(i, j int) bool { return alphabet[i] < alphabet[j] })
+	return &generator{d, pkg, fn, description, alphabet}
+}
+
+// header writes the comment and package clause common to both the
+// matcher and its test.
+func (g *generator) header(b *bytes.Buffer) {
+	fmt.Fprintf(b, "// Code generated by dfagen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(b, "package %s\n\n", g.pkg)
+}
+
+// source returns the formatted source of the matcher. The matcher
+// is a loop over the runes of its input containing a switch on the
+// current state, in which each case is a switch choosing the next
+// state from the current rune.
+func (g *generator) source() ([]byte, error) {
+	var b bytes.Buffer
+	g.header(&b)
+
+	fmt.Fprintf(&b, "// %s reports whether the entire string s matches %s.\n",
+		g.fn, g.description)
+	fmt.Fprintf(&b, "func %s(s string) bool {\n", g.fn)
+	if g.d.F.IsEmpty() {
+		fmt.Fprintf(&b, "return false\n}\n")
+		return format.Source(b.Bytes())
+	}
+	fmt.Fprintf(&b, "state := %d\n", g.d.Qs)
+	fmt.Fprintf(&b, "for _, r := range s {\n")
+	fmt.Fprintf(&b, "switch state {\n")
+	for q := 0; q < g.d.Q; q++ {
+		fmt.Fprintf(&b, "case %d:\n", q)
+		g.transitions(&b, q)
+	}
+	fmt.Fprintf(&b, "}\n}\n")
+
+	accepting := []string{}
+	for _, q := range g.d.F.Elements() {
+		accepting = append(accepting, strconv.Itoa(q))
+	}
+	fmt.Fprintf(&b, "switch state {\ncase %s:\nreturn true\n}\n",
+		strings.Join(accepting, ", "))
+	fmt.Fprintf(&b, "return false\n}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// transitions writes the switch choosing the next state from state
+// q. Runes moving to the same state share a case, with runs of
+// consecutive runes tested as ranges.
+func (g *generator) transitions(b *bytes.Buffer, q int) {
+	targets := []int{}
+	runes := make(map[int][]rune)
+	for _, letter := range g.alphabet {
+		if to, ok := g.d.D[q][letter]; ok {
+			if _, seen := runes[to]; !seen {
+				targets = append(targets, to)
+			}
+			runes[to] = append(runes[to], letter)
+		}
+	}
+
+	if len(targets) == 0 {
+		fmt.Fprintf(b, "return false\n")
+		return
+	}
+
+	fmt.Fprintf(b, "switch {\n")
+	for _, to := range targets {
+		fmt.Fprintf(b, "case %s:\n", condition(runes[to]))
+		if to != q {
+			fmt.Fprintf(b, "state = %d\n", to)
+		}
+	}
+	fmt.Fprintf(b, "default:\nreturn false\n}\n")
+}
+
+// condition returns an expression testing if r is one of the
+// provided runes, which must be sorted.
+func condition(runes []rune) string {
+	terms := []string{}
+	for i := 0; i < len(runes); {
+		j := i
+		for j+1 < len(runes) && runes[j+1] == runes[j]+1 {
+			j++
+		}
+		if j-i >= 2 {
+			terms = append(terms, fmt.Sprintf("r >= %s && r <= %s",
+				strconv.QuoteRune(runes[i]), strconv.QuoteRune(runes[j])))
+		} else {
+			for k := i; k <= j; k++ {
+				terms = append(terms, "r == "+strconv.QuoteRune(runes[k]))
+			}
+		}
+		i = j + 1
+	}
+	if len(terms) > 1 {
+		for i, term := range terms {
+			if strings.Contains(term, "&&") {
+				terms[i] = "(" + term + ")"
+			}
+		}
+	}
+	return strings.Join(terms, " || ")
+}
+
+// test returns the formatted source of a test for the matcher. The
+// test cases are short strings over the alphabet, together with a
+// string containing a rune outside it, and their expected results
+// are computed from the Dfa.
+func (g *generator) test() ([]byte, error) {
+	var b bytes.Buffer
+	g.header(&b)
+
+	fmt.Fprintf(&b, "import \"testing\"\n\n")
+	fmt.Fprintf(&b, "func Test%s(t *testing.T) {\n", g.fn)
+	fmt.Fprintf(&b, "testCases := []struct {\ninput string\nresult bool\n}{\n")
+	for _, s := range g.samples(maxSamples) {
+		fmt.Fprintf(&b, "{%s, %t},\n", strconv.Quote(s), g.d.Accepts(s))
+	}
+	fmt.Fprintf(&b, "}\n\n")
+	fmt.Fprintf(&b, "for _, tc := range testCases {\n")
+	fmt.Fprintf(&b, "if r := %s(tc.input); r != tc.result {\n", g.fn)
+	fmt.Fprintf(&b, "t.Errorf(\"input %%q, got %%v, want %%v\", tc.input, r, tc.result)\n")
+	fmt.Fprintf(&b, "}\n}\n}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// maxSamples is the maximum number of accepted, and of rejected,
+// strings used as test cases, and maxSampleLength is the maximum
+// length of those strings.
+const (
+	maxSamples      = 16
+	maxSampleLength = 8
+)
+
+// samples returns up to n accepted and n rejected strings, found by
+// enumerating strings over the alphabet in order of length, and a
+// string containing a rune outside the alphabet.
+func (g *generator) samples(n int) []string {
+	accepted, rejected := []string{}, []string{}
+	level := []string{""}
+	for length := 0; length <= maxSampleLength && len(level) > 0 &&
+		(len(accepted) < n || len(rejected) < n); length++ {
+		next := []string{}
+		for _, s := range level {
+			if g.d.Accepts(s) {
+				if len(accepted) < n {
+					accepted = append(accepted, s)
+				}
+			} else if len(rejected) < n {
+				rejected = append(rejected, s)
+			}
+			if len(next) < 64*n {
+				for _, letter := range g.alphabet {
+					next = append(next, s+string(letter))
+				}
+			}
+		}
+		level = next
+	}
+
+	outside := 'a'
+	for g.d.S.Contains(outside) {
+		outside++
+	}
+	samples := append(accepted, rejected...)
+	if len(accepted) > 0 {
+		samples = append(samples, accepted[len(accepted)-1]+string(outside))
+	}
+	return append(samples, string(outside))
+}
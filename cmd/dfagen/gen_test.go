@@ -0,0 +1,143 @@
+package main
+
+import (
+	"github.com/paulgriffiths/automata/regex"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCondition(t *testing.T) {
+	testCases := []struct {
+		runes  string
+		result string
+	}{
+		{"a", "r == 'a'"},
+		{"ab", "r == 'a' || r == 'b'"},
+		{"abc", "r >= 'a' && r <= 'c'"},
+		{"0123x", "(r >= '0' && r <= '3') || r == 'x'"},
+		{"\x00\n", "r == '\\x00' || r == '\\n'"},
+	}
+
+	for n, tc := range testCases {
+		if r := condition([]rune(tc.runes)); r != tc.result {
+			t.Errorf("case %d, got %q, want %q", n+1, r, tc.result)
+		}
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		snippet string
+	}{
+		{"(a|b)*abb", "case r == 'a':"},
+		{"x(0|1|2|3)*", "case r >= '0' && r <= '3':"},
+		{"a&b", "return false\n}"},
+	}
+
+	for n, tc := range testCases {
+		rex := regex.CompileWithOptions(tc.pattern,
+			regex.Options{Backend: regex.Derivatives})
+		g := newGenerator(rex.Dfa(), "gen", "Match", "a pattern")
+
+		src, err := g.source()
+		if err != nil {
+			t.Errorf("case %d, couldn't generate source: %v", n+1, err)
+			continue
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "", src, 0); err != nil {
+			t.Errorf("case %d, couldn't parse source: %v", n+1, err)
+		}
+		if !strings.Contains(string(src), tc.snippet) {
+			t.Errorf("case %d, source doesn't contain %q", n+1, tc.snippet)
+		}
+
+		test, err := g.test()
+		if err != nil {
+			t.Errorf("case %d, couldn't generate test: %v", n+1, err)
+			continue
+		}
+		if _, err := parser.ParseFile(token.NewFileSet(), "", test, 0); err != nil {
+			t.Errorf("case %d, couldn't parse test: %v", n+1, err)
+		}
+	}
+}
+
+func TestGeneratedPackage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping go toolchain run in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found")
+	}
+
+	testCases := []string{
+		"(a|b)*abb",
+		"x(0|1|2|3)*",
+		"((a|b)(1|2|3)*(c|d))*",
+		"a&b",
+		"~(a*b)",
+	}
+
+	for n, tc := range testCases {
+		rex := regex.CompileWithOptions(tc, regex.Options{Backend: regex.Derivatives})
+		g := newGenerator(rex.Dfa(), "gen", "Match", tc)
+
+		src, err := g.source()
+		if err != nil {
+			t.Errorf("case %d, couldn't generate source: %v", n+1, err)
+			continue
+		}
+		test, err := g.test()
+		if err != nil {
+			t.Errorf("case %d, couldn't generate test: %v", n+1, err)
+			continue
+		}
+
+		dir := t.TempDir()
+		files := map[string][]byte{
+			"go.mod":      []byte("module gen\n\ngo 1.21\n"),
+			"gen.go":      src,
+			"gen_test.go": test,
+		}
+		for name, data := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+				t.Fatalf("couldn't write %s: %v", name, err)
+			}
+		}
+
+		for _, args := range [][]string{{"vet", "."}, {"test", "."}} {
+			cmd := exec.Command(goTool, args...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "GOFLAGS=", "GOPROXY=off", "GOWORK=off")
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("case %d, go %s failed: %v\n%s", n+1, args[0], err, out)
+			}
+		}
+	}
+}
+
+func TestSamples(t *testing.T) {
+	rex := regex.Compile("(a|b)*abb")
+	g := newGenerator(rex.Dfa(), "gen", "Match", "a pattern")
+	samples := g.samples(4)
+
+	accepted, rejected := 0, 0
+	for _, s := range samples {
+		if g.d.Accepts(s) {
+			accepted++
+		} else {
+			rejected++
+		}
+	}
+	if accepted != 4 || rejected != 6 {
+		t.Errorf("got %d accepted and %d rejected, want 4 and 6",
+			accepted, rejected)
+	}
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/paulgriffiths/automata/regex"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// walker finds the files to be searched.
+type walker struct {
+	recursive  bool     // Search directories recursively
+	include    []string // If not empty, search only files matching one of these
+	exclude    []string // Skip files matching any of these
+	excludeDir []string // Skip directories matching any of these
+}
+
+// job is a file to be searched, numbered in the order in which its
+// output should be written. If an error occurred while finding the
+// file, it is reported instead of searching the file.
+type job struct {
+	index int
+	name  string
+	err   error
+}
+
+// result is the outcome of searching a file.
+type result struct {
+	index    int
+	output   []byte
+	selected bool
+	err      error
+}
+
+// walk sends a job for each file to be searched to the provided
+// channel, and closes the channel when done. The files are the named
+// files, and with recursive set, the files in the named directories
+// and their subdirectories, in lexical order. The name "-" stands
+// for the standard input. Include and exclude patterns are matched
+// against the base names of files and directories, but directories
+// named on the command line are never excluded.
+func (wk *walker) walk(names []string, jobs chan<- job) {
+	defer close(jobs)
+
+	index := 0
+	send := func(name string, err error) {
+		jobs <- job{index, name, err}
+		index++
+	}
+
+	for _, name := range names {
+		if name == "-" {
+			send(name, nil)
+			continue
+		}
+
+		info, err := os.Stat(name)
+		switch {
+		case err != nil:
+			send(name, err)
+			continue
+		case !info.IsDir():
+			if wk.selected(name) {
+				send(name, nil)
+			}
+			continue
+		case !wk.recursive:
+			send(name, fmt.Errorf("%s: is a directory", name))
+			continue
+		}
+
+		filepath.WalkDir(name, func(path string, d fs.DirEntry, err error) error {
+			switch {
+			case err != nil:
+				send(path, err)
+			case d.IsDir():
+				if path != name && matchAny(wk.excludeDir, d.Name()) {
+					return filepath.SkipDir
+				}
+			case d.Type().IsRegular() && wk.selected(path):
+				send(path, nil)
+			}
+			return nil
+		})
+	}
+}
+
+// selected returns true if the named file should be searched
+// according to the include and exclude patterns.
+func (wk *walker) selected(name string) bool {
+	base := filepath.Base(name)
+	if len(wk.include) > 0 && !matchAny(wk.include, base) {
+		return false
+	}
+	return !matchAny(wk.exclude, base)
+}
+
+// matchAny returns true if the name matches any of the provided
+// shell patterns.
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// search searches the files sent on jobs using the provided number
+// of workers, and writes their output to stdout, and any errors to
+// stderr, in the order of the jobs. It returns true if any lines were
+// selected, and true if any errors occurred. The number of files
+// searched ahead of the file whose output is next to be written is
+// limited, so that the buffered output of other files doesn't grow
+// without bound if one file is slow to search.
+func (c *config) search(jobs <-chan job, workers int, rex *regex.Regex,
+	stdin io.Reader, stdout, stderr io.Writer) (selected, failed bool) {
+	inflight := make(chan struct{}, 4*workers)
+	throttled := make(chan job)
+	go func() {
+		for j := range jobs {
+			inflight <- struct{}{}
+			throttled <- j
+		}
+		close(throttled)
+	}()
+
+	results := make(chan result)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range throttled {
+				results <- c.searchFile(j, rex, stdin)
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	pending := make(map[int]result)
+	next := 0
+	for r := range results {
+		pending[r.index] = r
+		for r, ok := pending[next]; ok; r, ok = pending[next] {
+			delete(pending, next)
+			next++
+			<-inflight
+
+			stdout.Write(r.output)
+			if r.err != nil {
+				fmt.Fprintf(stderr, "match: %v\n", r.err)
+				failed = true
+			}
+			selected = selected || r.selected
+		}
+	}
+
+	return selected, failed
+}
+
+// searchFile searches the file for a job, buffering its output.
+func (c *config) searchFile(j job, rex *regex.Regex, stdin io.Reader) result {
+	r := result{index: j.index, err: j.err}
+	if r.err == nil {
+		var buf bytes.Buffer
+		r.selected, r.err = c.grepFile(&buf, stdin, j.name, rex)
+		r.output = buf.Bytes()
+	}
+	return r
+}
+
+// grepFile searches the named file, or the standard input if the
+// name is "-", closing the file before returning.
+func (c *config) grepFile(w io.Writer, stdin io.Reader, filename string,
+	rex *regex.Regex) (bool, error) {
+	if filename == "-" {
+		return c.grep(w, stdin, stdinName, rex)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	selected, err := c.grep(w, f, filename, rex)
+	if err != nil {
+		return selected, fmt.Errorf("%s: %v", filename, err)
+	}
+	return selected, nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"github.com/paulgriffiths/automata/regex"
+	"io"
+)
+
+// config holds the options controlling how input is searched and
+// how results are written.
+type config struct {
+	invert         bool // Select non-matching lines
+	count          bool // Write only a count of selected lines
+	lineNumbers    bool // Prefix lines with their line numbers
+	filenames      bool // Prefix lines with the name of their file
+	onlyMatching   bool // Write only the matching parts of lines
+	listMatches    bool // Write only the names of files with selected lines
+	listNonMatches bool // Write only the names of files without them
+	wholeLine      bool // Match only whole lines
+	text           bool // Search binary files as if they were text
+}
+
+// binaryCheckLength is the number of bytes at the start of an input
+// checked for a NUL byte, which marks the input as binary.
+const binaryCheckLength = 8192
+
+// maxLineLength is the length of the longest line which can be read.
+const maxLineLength = 1 << 30
+
+// grep searches the lines of the provided input for matches of the
+// regular expression and writes the results to w according to the
+// configuration, using name as the name of the input. If the input
+// is binary, which is taken to be the case if a NUL byte appears near
+// its start, selected lines are not written; instead, a message is
+// written saying that the input matches. It returns
+// true if any lines were selected, or, when listing files without
+// selected lines, if the name of the input was written.
+func (c *config) grep(w io.Writer, input io.Reader, name string,
+	rex *regex.Regex) (bool, error) {
+	prefix := ""
+	if c.filenames {
+		prefix = name + ":"
+	}
+
+	reader := bufio.NewReaderSize(input, binaryCheckLength)
+	binary := false
+	if !c.text {
+		head, _ := reader.Peek(binaryCheckLength)
+		binary = bytes.IndexByte(head, 0) != -1
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxLineLength)
+	selected := 0
+
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if c.matches(rex, line) == c.invert {
+			continue
+		}
+
+		selected++
+		switch {
+		case c.listMatches:
+			fmt.Fprintf(w, "%s\n", name)
+			return true, nil
+		case c.listNonMatches || c.count:
+			continue
+		case binary:
+			fmt.Fprintf(w, "Binary file %s matches\n", name)
+			return true, nil
+		}
+
+		linePrefix := prefix
+		if c.lineNumbers {
+			linePrefix += fmt.Sprintf("%d:", n)
+		}
+
+		switch {
+		case c.onlyMatching && c.invert:
+			// There are no matching parts of non-matching lines.
+		case c.onlyMatching && c.wholeLine:
+			fmt.Fprintf(w, "%s%s\n", linePrefix, line)
+		case c.onlyMatching:
+			for _, match := range rex.FindAllString(line, -1) {
+				if match != "" {
+					fmt.Fprintf(w, "%s%s\n", linePrefix, match)
+				}
+			}
+		default:
+			fmt.Fprintf(w, "%s%s\n", linePrefix, line)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return selected > 0, err
+	}
+
+	switch {
+	case c.listNonMatches:
+		if selected == 0 {
+			fmt.Fprintf(w, "%s\n", name)
+		}
+		return selected == 0, nil
+	case c.listMatches:
+		return false, nil
+	case c.count:
+		fmt.Fprintf(w, "%s%d\n", prefix, selected)
+	}
+
+	return selected > 0, nil
+}
+
+// matches returns true if the line, or with wholeLine set, the whole
+// line, matches the regular expression.
+func (c *config) matches(rex *regex.Regex, line string) bool {
+	if c.wholeLine {
+		return rex.Match(line)
+	}
+	return rex.FindStringIndex(line) != nil
+}
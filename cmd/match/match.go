@@ -1,51 +1,185 @@
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"github.com/paulgriffiths/automata/regex"
+	"io"
 	"os"
+	"runtime"
+	"strings"
 )
 
+// Exit statuses, as for grep.
+const (
+	exitSelected   = 0 // One or more lines were selected
+	exitUnselected = 1 // No lines were selected
+	exitError      = 2 // An error occurred
+)
+
+// stdinName is the name used for the standard input.
+const stdinName = "(standard input)"
+
+// patterns collects the patterns provided with repeated -e flags.
+type patterns []string
+
+func (p *patterns) String() string {
+	return strings.Join(*p, ", ")
+}
+
+func (p *patterns) Set(s string) error {
+	*p = append(*p, s)
+	return nil
+}
+
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Fprintf(os.Stderr, "match: missing regular expression\n")
-		os.Exit(1)
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command with the provided arguments, excluding the
+// program name, and returns its exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var c config
+	var exprs patterns
+	var wk walker
+	var withFilenames, noFilenames, ignoreCase bool
+	var includes, excludes, excludeDirs patterns
+	var workers int
+
+	flags := flag.NewFlagSet("match", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.BoolVar(&c.invert, "v", false, "select non-matching lines")
+	flags.BoolVar(&c.count, "c", false, "print only a count of selected lines")
+	flags.BoolVar(&c.lineNumbers, "n", false, "prefix lines with line numbers")
+	flags.BoolVar(&withFilenames, "H", false, "always prefix lines with file names")
+	flags.BoolVar(&noFilenames, "h", false, "never prefix lines with file names")
+	flags.BoolVar(&c.onlyMatching, "o", false, "print only the matching parts of lines")
+	flags.BoolVar(&c.listMatches, "l", false, "print only names of files with selected lines")
+	flags.BoolVar(&c.listNonMatches, "L", false, "print only names of files without selected lines")
+	flags.BoolVar(&ignoreCase, "i", false, "ignore case")
+	flags.Var(&exprs, "e", "use `pattern` for matching; may be repeated")
+	flags.BoolVar(&c.wholeLine, "x", false, "match only whole lines")
+	flags.BoolVar(&wk.recursive, "r", false, "search directories recursively")
+	flags.Var(&includes, "include", "search only files matching `glob`; may be repeated")
+	flags.Var(&excludes, "exclude", "skip files matching `glob`; may be repeated")
+	flags.Var(&excludeDirs, "exclude-dir", "skip directories matching `glob`; may be repeated")
+	flags.BoolVar(&c.text, "a", false, "search binary files as if they were text")
+	flags.IntVar(&workers, "j", runtime.NumCPU(), "search `n` files concurrently")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "usage: match [flags] pattern [file ...]\n")
+		fmt.Fprintf(stderr, "       match [flags] -e pattern ... [file ...]\n")
+		flags.PrintDefaults()
 	}
 
-	rex := regex.Compile(os.Args[1])
-	if rex == nil {
-		fmt.Fprintf(os.Stderr, "match: invalid regular expression\n")
-		os.Exit(1)
+	if err := flags.Parse(expandArgs(args)); err != nil {
+		return exitError
 	}
 
-	infiles := []*os.File{}
-	if len(os.Args) > 2 {
-		for _, filename := range os.Args[2:] {
-			f, err := os.Open(filename)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "match: couldn't open file %s: %v\n",
-					filename, err)
-				os.Exit(1)
-			}
-			infiles = append(infiles, f)
+	wk.include, wk.exclude, wk.excludeDir = includes, excludes, excludeDirs
+	files := flags.Args()
+	if len(exprs) == 0 {
+		if len(files) == 0 {
+			fmt.Fprintf(stderr, "match: missing regular expression\n")
+			flags.Usage()
+			return exitError
+		}
+		exprs, files = patterns{files[0]}, files[1:]
+	}
+
+	rex, err := compile(exprs, ignoreCase)
+	if err != nil {
+		fmt.Fprintf(stderr, "match: %v\n", err)
+		return exitError
+	}
+
+	if len(files) == 0 {
+		if wk.recursive {
+			files = []string{"."}
+		} else {
+			files = []string{"-"}
 		}
-	} else {
-		infiles = append(infiles, os.Stdin)
 	}
+	c.filenames = !noFilenames &&
+		(withFilenames || wk.recursive || len(files) > 1)
+	if workers < 1 {
+		workers = 1
+	}
+
+	jobs := make(chan job)
+	go wk.walk(files, jobs)
+	selected, failed := c.search(jobs, workers, rex, stdin, stdout, stderr)
+
+	switch {
+	case failed:
+		return exitError
+	case selected:
+		return exitSelected
+	}
+	return exitUnselected
+}
 
-	for _, f := range infiles {
-		input := bufio.NewScanner(f)
-		for input.Scan() {
-			if rex.Match(input.Text()) {
-				fmt.Printf("%s\n", input.Text())
+// compile compiles the patterns into a single regular expression
+// matching any of them.
+func compile(exprs []string, ignoreCase bool) (*regex.Regex, error) {
+	opts := regex.Options{Backend: regex.Derivatives, FoldCase: ignoreCase}
+	for _, expr := range exprs {
+		if regex.CompileWithOptions(expr, opts) == nil {
+			return nil, fmt.Errorf("invalid regular expression %q", expr)
+		}
+	}
+
+	combined := exprs[0]
+	if len(exprs) > 1 {
+		combined = "(" + strings.Join(exprs, ")|(") + ")"
+	}
+	return regex.CompileWithOptions(combined, opts), nil
+}
+
+// valueFlags are the single-letter flags which take a value, and
+// longValueFlags are the longer flags which do.
+const valueFlags = "ej"
+
+var longValueFlags = map[string]bool{
+	"include":     true,
+	"exclude":     true,
+	"exclude-dir": true,
+}
+
+// expandArgs splits combined single-letter flags, such as -vn, into
+// separate flags, as grep allows. Since -e and -j take values,
+// anything following them in a combined argument is their value, so
+// that -ie abc and -ieabc are both equivalent to -i -e abc. Arguments
+// after -- or the first non-flag argument are left unchanged, as are
+// longer flags such as --include, although their values are skipped.
+func expandArgs(args []string) []string {
+	expanded := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || arg == "-" || !strings.HasPrefix(arg, "-") {
+			return append(expanded, args[i:]...)
+		}
+		if strings.HasPrefix(arg, "--") || strings.Contains(arg, "=") {
+			expanded = append(expanded, arg)
+			if longValueFlags[strings.TrimLeft(arg, "-")] && i+1 < len(args) {
+				i++
+				expanded = append(expanded, args[i])
 			}
+			continue
 		}
-		if f != os.Stdin {
-			if err := f.Close(); err != nil {
-				fmt.Fprintf(os.Stderr, "match: error closing file: %v\n",
-					err)
+
+		for j, letter := range arg[1:] {
+			expanded = append(expanded, "-"+string(letter))
+			if !strings.ContainsRune(valueFlags, letter) {
+				continue
+			}
+			if value := arg[j+2:]; value != "" {
+				expanded = append(expanded, value)
+			} else if i+1 < len(args) {
+				i++
+				expanded = append(expanded, args[i])
 			}
+			break
 		}
 	}
+	return expanded
 }
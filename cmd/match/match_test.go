@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandArgs(t *testing.T) {
+	testCases := []struct {
+		args, result string
+	}{
+		{"-v -n abc", "-v -n abc"},
+		{"-vn abc", "-v -n abc"},
+		{"-ie abc -vc file", "-i -e abc -v -c file"},
+		{"-ieabc -e def", "-i -e abc -e def"},
+		{"-e=abc -x", "-e=abc -x"},
+		{"-n abc -vc", "-n abc -vc"},
+		{"-n -- -vc", "-n -- -vc"},
+		{"-j 4 -rn abc", "-j 4 -r -n abc"},
+		{"-rj4 abc", "-r -j 4 abc"},
+		{"--include *.go -rn abc", "--include *.go -r -n abc"},
+		{"--include=*.go -rn abc", "--include=*.go -r -n abc"},
+	}
+
+	for n, tc := range testCases {
+		got := expandArgs(strings.Fields(tc.args))
+		if want := strings.Fields(tc.result); !reflect.DeepEqual(got, want) {
+			t.Errorf("case %d, got %q, want %q", n+1, got, want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"one.txt": "ab\nabc\nxyz\nAB\ncabab\n",
+		"two.txt": "xyz\nzzz\n",
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("couldn't write file: %v", err)
+		}
+	}
+	one, two := filepath.Join(dir, "one.txt"), filepath.Join(dir, "two.txt")
+
+	testCases := []struct {
+		args   []string
+		stdin  string
+		output string
+		status int
+	}{
+		{[]string{"ab", one}, "", "ab\nabc\ncabab\n", 0},
+		{[]string{"-x", "ab", one}, "", "ab\n", 0},
+		{[]string{"-i", "-x", "ab", one}, "", "ab\nAB\n", 0},
+		{[]string{"-v", "ab", one}, "", "xyz\nAB\n", 0},
+		{[]string{"-c", "ab", one}, "", "3\n", 0},
+		{[]string{"-cv", "ab", one}, "", "2\n", 0},
+		{[]string{"-n", "z", one}, "", "3:xyz\n", 0},
+		{[]string{"-o", "ab", one}, "", "ab\nab\nab\nab\n", 0},
+		{[]string{"-on", "c(ab)*", one}, "", "2:c\n5:cabab\n", 0},
+		{[]string{"-ov", "ab", one}, "", "", 0},
+		{[]string{"ab", one, two}, "", one + ":ab\n" + one + ":abc\n" +
+			one + ":cabab\n", 0},
+		{[]string{"-h", "z", one, two}, "", "xyz\nxyz\nzzz\n", 0},
+		{[]string{"-H", "-c", "z", one}, "", one + ":1\n", 0},
+		{[]string{"-c", "z", one, two}, "", one + ":1\n" + two + ":2\n", 0},
+		{[]string{"-l", "ab", one, two}, "", one + "\n", 0},
+		{[]string{"-L", "ab", one, two}, "", two + "\n", 0},
+		{[]string{"-L", "z", one, two}, "", "", 1},
+		{[]string{"-e", "abc", "-e", "zz", one, two}, "", one + ":abc\n" +
+			two + ":zzz\n", 0},
+		{[]string{"q"}, "aqa\nbbb\n", "aqa\n", 0},
+		{[]string{"-H", "q", "-"}, "aqa\n", stdinName + ":aqa\n", 0},
+		{[]string{"q", one}, "", "", 1},
+		{[]string{"-h", "ab", filepath.Join(dir, "missing.txt"), one}, "",
+			"ab\nabc\ncabab\n", 2},
+		{[]string{"a|"}, "", "", 2},
+		{[]string{}, "", "", 2},
+		{[]string{"-y", "a"}, "", "", 2},
+	}
+
+	for n, tc := range testCases {
+		var stdout, stderr bytes.Buffer
+		status := run(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+		if status != tc.status {
+			t.Errorf("case %d, got status %d, want %d", n+1, status, tc.status)
+		}
+		if got := stdout.String(); got != tc.output {
+			t.Errorf("case %d, got output %q, want %q", n+1, got, tc.output)
+		}
+		if (tc.status == 2) != (stderr.Len() > 0) {
+			t.Errorf("case %d, unexpected error output %q", n+1, stderr.String())
+		}
+	}
+}
+
+func TestRunRecursive(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt":            "ab\nxy\n",
+		"b.go":             "ab\n",
+		"sub/c.txt":        "cab\n",
+		"sub/deep/d.txt":   "zz\nab\n",
+		"skip/e.txt":       "ab\n",
+		"bin/data.bin":     "ab\x00cd\nab\n",
+		"sub/deep/none.go": "xyz\n",
+	}
+	for name, contents := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("couldn't create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("couldn't write file: %v", err)
+		}
+	}
+
+	// Paths in output are relative to dir, with forward slashes.
+	testCases := []struct {
+		args   []string
+		output string
+		status int
+	}{
+		{[]string{"-r", "ab", "."}, "a.txt:ab\nb.go:ab\n" +
+			"Binary file bin/data.bin matches\nskip/e.txt:ab\n" +
+			"sub/c.txt:cab\nsub/deep/d.txt:ab\n", 0},
+		{[]string{"-rn", "ab"}, "a.txt:1:ab\nb.go:1:ab\n" +
+			"Binary file bin/data.bin matches\nskip/e.txt:1:ab\n" +
+			"sub/c.txt:1:cab\nsub/deep/d.txt:2:ab\n", 0},
+		{[]string{"-ra", "ab", "bin"}, "bin/data.bin:ab\x00cd\nbin/data.bin:ab\n", 0},
+		{[]string{"-rc", "ab", "bin"}, "bin/data.bin:2\n", 0},
+		{[]string{"-r", "--include=*.txt", "--exclude-dir=skip",
+			"--exclude-dir=bin", "ab"}, "a.txt:ab\nsub/c.txt:cab\n" +
+			"sub/deep/d.txt:ab\n", 0},
+		{[]string{"-rL", "--exclude=*.txt", "ab"}, "sub/deep/none.go\n", 0},
+		{[]string{"-r", "--include=*.md", "ab"}, "", 1},
+		{[]string{"ab", "sub"}, "", 2},
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("couldn't get working directory: %v", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("couldn't change directory: %v", err)
+	}
+	defer os.Chdir(wd)
+
+	for n, tc := range testCases {
+		for _, workers := range []string{"1", "8"} {
+			var stdout, stderr bytes.Buffer
+			args := append([]string{"-j", workers}, tc.args...)
+			status := run(args, strings.NewReader(""), &stdout, &stderr)
+			if status != tc.status {
+				t.Errorf("case %d, -j %s, got status %d, want %d",
+					n+1, workers, status, tc.status)
+			}
+			got := strings.Replace(stdout.String(), "./", "", -1)
+			if got = filepath.ToSlash(got); got != tc.output {
+				t.Errorf("case %d, -j %s, got output %q, want %q",
+					n+1, workers, got, tc.output)
+			}
+		}
+	}
+}
+
+// Output must appear in the order of the files, however many workers
+// search them.
+func TestRunOrdering(t *testing.T) {
+	dir := t.TempDir()
+	args := []string{"-j", "16", "-c", "a"}
+	want := ""
+	for i := 0; i < 200; i++ {
+		path := filepath.Join(dir, fmt.Sprintf("file%03d.txt", i))
+		contents := strings.Repeat("ba\n", i)
+		if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+			t.Fatalf("couldn't write file: %v", err)
+		}
+		args = append(args, path)
+		want += fmt.Sprintf("%s:%d\n", path, i)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if status := run(args, strings.NewReader(""), &stdout, &stderr); status != 0 {
+		t.Errorf("got status %d, want 0", status)
+	}
+	if got := stdout.String(); got != want {
+		t.Errorf("output out of order")
+	}
+}
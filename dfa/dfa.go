@@ -1,6 +1,9 @@
 package dfa
 
-import "github.com/paulgriffiths/gods/sets"
+import (
+	"github.com/paulgriffiths/gods/sets"
+	"unicode/utf8"
+)
 
 // Dfa implements a deterministic finite automaton.
 type Dfa struct {
@@ -13,22 +16,24 @@ type Dfa struct {
 
 // Accepts returns true if the DFA accepts the provided string.
 func (d Dfa) Accepts(input string) bool {
-	currentState := d.Qs
-	ok := false
+	// Running the Dfa does not need its alphabet.
+	m := Machine[rune]{Q: d.Q, D: d.D, Qs: d.Qs, F: d.F}
+	currentState, ok := m.Qs, false
 
 	for _, letter := range input {
-		currentState, ok = d.D[currentState][letter]
-		if !ok {
+		if currentState, ok = m.step(currentState, letter); !ok {
 			return false
 		}
 	}
 
-	return d.F.Contains(currentState)
+	return m.F.Contains(currentState)
 }
 
 // AcceptsPrefix checks if there is a prefix of the provided string
 // which is accepted by the DFA. If it is, the function returns true
-// and the length of the prefix. Otherwise, it returns false and zero.
+// and the length in bytes of the longest such prefix. Otherwise, it
+// returns false and zero. The empty prefix is only considered when
+// the string itself is empty.
 func (d Dfa) AcceptsPrefix(input string) (bool, int) {
 	currentState := d.Qs
 	ok := false
@@ -39,14 +44,16 @@ func (d Dfa) AcceptsPrefix(input string) (bool, int) {
 		return true, 0
 	}
 
-	for n, letter := range input {
+	for n := 0; n < len(input); {
+		letter, size := utf8.DecodeRuneInString(input[n:])
+		n += size
 		currentState, ok = d.D[currentState][letter]
 		if !ok {
 			break
 		}
 		if d.F.Contains(currentState) {
 			matches = true
-			longest = n + 1
+			longest = n
 		}
 	}
 
@@ -108,3 +108,39 @@ func TestAcceptsPrefixM4(t *testing.T) {
 		}
 	}
 }
+
+// The length of the accepted prefix is in bytes, not runes.
+func TestAcceptsPrefixMultibyte(t *testing.T) {
+	d := dfa.Dfa{
+		Q: 3,
+		S: sets.NewSetRune('é', 'x'),
+		D: []map[rune]int{
+			{'é': 1},
+			{'x': 2, 'é': 1},
+			{},
+		},
+		Qs: 0,
+		F:  sets.NewSetInt(1, 2),
+	}
+
+	testCases := []struct {
+		input   string
+		matches bool
+		length  int
+	}{
+		{"é", true, 2},
+		{"éé", true, 4},
+		{"éx", true, 3},
+		{"ééxé", true, 5},
+		{"éy", true, 2},
+		{"x", false, 0},
+	}
+
+	for _, c := range testCases {
+		m, l := d.AcceptsPrefix(c.input)
+		if m != c.matches || l != c.length {
+			t.Errorf("input %q, got (%t, %d), want (%t, %d)",
+				c.input, m, l, c.matches, c.length)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package dfa
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/gods/sets"
+)
+
+// Builder constructs a Dfa one state and transition at a time,
+// keeping the number of states, the alphabet and the transition
+// function consistent. The zero value is not usable; use NewBuilder.
+type Builder struct {
+	d        Dfa
+	problems []string
+}
+
+// NewBuilder returns a Builder for a Dfa with no states. Unless
+// SetStart is called, the start state is state 0.
+func NewBuilder() *Builder {
+	return &Builder{
+		d: Dfa{
+			S: sets.NewSetRune(),
+			F: sets.NewSetInt(),
+		},
+	}
+}
+
+// AddState adds a new, non-accepting state with no transitions, and
+// returns its number.
+func (b *Builder) AddState() int {
+	b.d.D = append(b.d.D, make(map[rune]int))
+	b.d.Q++
+	return b.d.Q - 1
+}
+
+// AddTransition adds a transition from state from to state to on the
+// provided rune, and adds the rune to the alphabet. The target state
+// need not have been added yet, but must have been by the time Build
+// is called. Adding a transition on a rune for which the state
+// already has a transition to a different state is an error which is
+// reported by Build.
+func (b *Builder) AddTransition(from int, r rune, to int) {
+	if from < 0 || from >= b.d.Q {
+		b.addProblem("state %d, rune %q: source state out of range", from, r)
+		return
+	}
+	if prev, ok := b.d.D[from][r]; ok && prev != to {
+		b.addProblem("state %d, rune %q: conflicting target states %d and %d",
+			from, r, prev, to)
+		return
+	}
+	b.d.S.Insert(r)
+	b.d.D[from][r] = to
+}
+
+// SetStart sets the start state.
+func (b *Builder) SetStart(q int) {
+	b.d.Qs = q
+}
+
+// SetAccepting makes the provided state an accepting state.
+func (b *Builder) SetAccepting(q int) {
+	b.d.F.Insert(q)
+}
+
+// Build returns the constructed Dfa. If any errors occurred while
+// building it, or it is not well-formed, the error is a
+// *ValidationError describing every problem found. The Builder may
+// continue to be used, and does not share any state with the
+// returned Dfa.
+func (b *Builder) Build() (Dfa, error) {
+	result := Dfa{
+		Q:  b.d.Q,
+		S:  sets.NewSetRune(b.d.S.Elements()...),
+		D:  make([]map[rune]int, b.d.Q),
+		Qs: b.d.Qs,
+		F:  sets.NewSetInt(b.d.F.Elements()...),
+	}
+	for q, trans := range b.d.D {
+		result.D[q] = make(map[rune]int)
+		for letter, to := range trans {
+			result.D[q][letter] = to
+		}
+	}
+
+	problems := append([]string{}, b.problems...)
+	if err := result.Validate(); err != nil {
+		problems = append(problems, err.(*ValidationError).Problems...)
+	}
+	if len(problems) > 0 {
+		return Dfa{}, &ValidationError{problems}
+	}
+	return result, nil
+}
+
+// addProblem records an error to be reported by Build.
+func (b *Builder) addProblem(format string, args ...interface{}) {
+	b.problems = append(b.problems, fmt.Sprintf(format, args...))
+}
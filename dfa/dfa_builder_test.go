@@ -0,0 +1,66 @@
+package dfa_test
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := dfa.NewBuilder()
+	q0, q1, q2 := b.AddState(), b.AddState(), b.AddState()
+	b.AddTransition(q0, '0', q0)
+	b.AddTransition(q0, '1', q1)
+	b.AddTransition(q1, '0', q2)
+	b.AddTransition(q1, '1', q1)
+	b.AddTransition(q2, '0', q1)
+	b.AddTransition(q2, '1', q1)
+	b.SetStart(q0)
+	b.SetAccepting(q1)
+
+	d, err := b.Build()
+	if err != nil {
+		t.Fatalf("couldn't build Dfa: %v", err)
+	}
+	if !equalDfas(d, m1) {
+		t.Errorf("got %v, want %v", d, m1)
+	}
+
+	// The built Dfa must not share state with the Builder.
+	b.AddTransition(q0, '2', q2)
+	b.SetAccepting(q0)
+	if d.S.Contains('2') || len(d.D[q0]) != 2 || d.F.Contains(q0) {
+		t.Errorf("Dfa changed after building")
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	b := dfa.NewBuilder()
+	b.AddState()
+	b.AddTransition(0, 'a', 0)
+	b.AddTransition(0, 'a', 1)
+	b.AddTransition(0, 'a', 0)
+	b.AddTransition(1, 'b', 0)
+	b.AddTransition(0, 'b', 2)
+	b.SetAccepting(5)
+
+	want := []string{
+		"state 0, rune 'a': conflicting target states 0 and 1",
+		"state 1, rune 'b': source state out of range",
+		"accepting state 5 out of range",
+		"state 0, rune 'b': target state 2 out of range",
+	}
+
+	_, err := b.Build()
+	verr, ok := err.(*dfa.ValidationError)
+	if !ok {
+		t.Fatalf("got %v, want *ValidationError", err)
+	}
+	if !reflect.DeepEqual(verr.Problems, want) {
+		t.Errorf("got %q, want %q", verr.Problems, want)
+	}
+
+	if _, err := dfa.NewBuilder().Build(); err == nil {
+		t.Errorf("built Dfa with no states")
+	}
+}
@@ -0,0 +1,55 @@
+package dfa
+
+import (
+	"github.com/paulgriffiths/automata/internal/dot"
+	"github.com/paulgriffiths/gods/sets"
+	"io"
+)
+
+// DotOptions controls the output of WriteDot.
+type DotOptions struct {
+	Name      string        // Graph name, "dfa" if empty
+	StateSets []sets.SetInt // NFA states represented by each DFA state
+}
+
+// WriteDot writes a description of the Dfa in the Graphviz DOT
+// language to w. Accepting states are drawn with a double circle,
+// and transitions between the same two states are merged into a
+// single edge whose label lists their runes, with runs of
+// consecutive runes shown as ranges such as a-z. If opts.StateSets
+// is provided, for instance from nfa.Nfa.ToDfaStates, each state is
+// also labeled with the corresponding set of NFA states.
+func (d Dfa) WriteDot(w io.Writer, opts DotOptions) error {
+	name := opts.Name
+	if name == "" {
+		name = "dfa"
+	}
+
+	g := dot.New(name)
+	g.Start(d.Qs)
+	for q := 0; q < d.Q; q++ {
+		annotation := ""
+		if q < len(opts.StateSets) {
+			annotation = dot.Set(opts.StateSets[q].Elements())
+		}
+		g.State(q, annotation, d.F.Contains(q))
+	}
+
+	alphabet := d.alphabet()
+	for from, trans := range d.D {
+		edges := make(map[int][]rune)
+		for _, letter := range alphabet {
+			if to, ok := trans[letter]; ok {
+				edges[to] = append(edges[to], letter)
+			}
+		}
+		for to := 0; to < d.Q; to++ {
+			if letters, ok := edges[to]; ok {
+				g.Edge(from, to, dot.Label(letters))
+			}
+		}
+	}
+
+	_, err := g.WriteTo(w)
+	return err
+}
@@ -0,0 +1,80 @@
+package dfa_test
+
+import (
+	"bytes"
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/gods/sets"
+	"testing"
+)
+
+func TestWriteDot(t *testing.T) {
+	d := dfa.Dfa{
+		Q: 3,
+		S: sets.NewSetRune('a', 'b', 'c', 'x', 'y', '-'),
+		D: []map[rune]int{
+			{'a': 1, 'b': 1, 'c': 1, 'x': 2},
+			{'x': 1, 'y': 1, '-': 2},
+			{},
+		},
+		Qs: 0,
+		F:  sets.NewSetInt(2),
+	}
+
+	testCases := []struct {
+		opts dfa.DotOptions
+		want string
+	}{
+		{
+			dfa.DotOptions{},
+			`digraph "dfa" {
+	rankdir=LR;
+	node [shape=circle];
+	start [shape=point];
+	start -> 0;
+	0 [label="0", shape=circle];
+	1 [label="1", shape=circle];
+	2 [label="2", shape=doublecircle];
+	0 -> 1 [label="a-c"];
+	0 -> 2 [label="x"];
+	1 -> 1 [label="x,y"];
+	1 -> 2 [label="'-'"];
+}
+`,
+		},
+		{
+			dfa.DotOptions{
+				Name: "M",
+				StateSets: []sets.SetInt{
+					sets.NewSetInt(0, 1),
+					sets.NewSetInt(2),
+					sets.NewSetInt(3, 4),
+				},
+			},
+			`digraph "M" {
+	rankdir=LR;
+	node [shape=circle];
+	start [shape=point];
+	start -> 0;
+	0 [label="0\n{0,1}", shape=circle];
+	1 [label="1\n{2}", shape=circle];
+	2 [label="2\n{3,4}", shape=doublecircle];
+	0 -> 1 [label="a-c"];
+	0 -> 2 [label="x"];
+	1 -> 1 [label="x,y"];
+	1 -> 2 [label="'-'"];
+}
+`,
+		},
+	}
+
+	for n, tc := range testCases {
+		var b bytes.Buffer
+		if err := d.WriteDot(&b, tc.opts); err != nil {
+			t.Errorf("case %d, couldn't write DOT: %v", n+1, err)
+			continue
+		}
+		if b.String() != tc.want {
+			t.Errorf("case %d, got\n%s\nwant\n%s", n+1, b.String(), tc.want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package dfa
+
+import "github.com/paulgriffiths/gods/sets"
+
+// FromKeywords returns a deterministic finite automaton which accepts
+// exactly the provided keywords. The automaton is a trie: state 0 is
+// the start state, every other state is reached by exactly one
+// transition, and the states are numbered in the order in which the
+// keywords first reach them. Its alphabet is the set of runes
+// appearing in the keywords. It is built in time proportional to the
+// total length of the keywords, and is typically much faster to
+// construct than an equivalent union of automata.
+func FromKeywords(words []string) Dfa {
+	d := Dfa{
+		Q:  1,
+		S:  sets.NewSetRune(),
+		D:  []map[rune]int{make(map[rune]int)},
+		Qs: 0,
+		F:  sets.NewSetInt(),
+	}
+
+	for _, word := range words {
+		state := 0
+		for _, letter := range word {
+			next, ok := d.D[state][letter]
+			if !ok {
+				next = d.Q
+				d.D = append(d.D, make(map[rune]int))
+				d.Q++
+				d.D[state][letter] = next
+				d.S.Insert(letter)
+			}
+			state = next
+		}
+		d.F.Insert(state)
+	}
+
+	return d
+}
@@ -0,0 +1,69 @@
+package dfa_test
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"testing"
+)
+
+func TestFromKeywords(t *testing.T) {
+	testCases := []struct {
+		words  []string
+		states int
+		accept []string
+		reject []string
+	}{
+		{[]string{}, 1, []string{}, []string{"", "a"}},
+		{[]string{""}, 1, []string{""}, []string{"a"}},
+		{
+			[]string{"he", "she", "his", "hers"},
+			10,
+			[]string{"he", "she", "his", "hers"},
+			[]string{"", "h", "her", "hershe", "sh", "hi", "x"},
+		},
+		{
+			[]string{"ab", "ab", "abc", "a"},
+			4,
+			[]string{"a", "ab", "abc"},
+			[]string{"", "b", "abcd", "aab"},
+		},
+		{
+			[]string{"ñu", "€", "ñ"},
+			4,
+			[]string{"ñu", "€", "ñ"},
+			[]string{"u", "ñuñu"},
+		},
+	}
+
+	for n, tc := range testCases {
+		d := dfa.FromKeywords(tc.words)
+		if err := d.Validate(); err != nil {
+			t.Errorf("case %d, invalid automaton: %v", n+1, err)
+			continue
+		}
+		if d.Q != tc.states {
+			t.Errorf("case %d, got %d states, want %d", n+1, d.Q, tc.states)
+		}
+		for _, s := range tc.accept {
+			if !d.Accepts(s) {
+				t.Errorf("case %d, string %q not accepted", n+1, s)
+			}
+		}
+		for _, s := range tc.reject {
+			if d.Accepts(s) {
+				t.Errorf("case %d, string %q accepted", n+1, s)
+			}
+		}
+	}
+}
+
+func TestFromKeywordsMinimize(t *testing.T) {
+	d := dfa.FromKeywords([]string{"tap", "taps", "top", "tops"}).Minimize()
+	if d.Q != 5 {
+		t.Errorf("got %d states, want 5", d.Q)
+	}
+	for _, s := range []string{"tap", "taps", "top", "tops"} {
+		if !d.Accepts(s) {
+			t.Errorf("string %q not accepted", s)
+		}
+	}
+}
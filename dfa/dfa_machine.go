@@ -0,0 +1,124 @@
+package dfa
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/gods/sets"
+)
+
+// Machine is a deterministic finite automaton over an alphabet of
+// symbols of any comparable type, such as an enumeration of events.
+// It has the same fields as a Dfa, except that the alphabet is a
+// slice, which should not hold duplicates, and every symbol with a
+// transition should be in it. A Dfa is a Machine over runes, and
+// its methods are implemented by the corresponding methods of the
+// Machine returned by FromDfa.
+type Machine[T comparable] struct {
+	Q  int         // Number of states
+	S  []T         // Alphabet
+	D  []map[T]int // Transition function
+	Qs int         // Start state
+	F  sets.SetInt // Set of accepting states
+}
+
+// Accepts returns true if the Machine accepts the provided sequence
+// of symbols.
+func (m Machine[T]) Accepts(input []T) bool {
+	currentState, ok := m.Qs, false
+
+	for _, symbol := range input {
+		if currentState, ok = m.step(currentState, symbol); !ok {
+			return false
+		}
+	}
+
+	return m.F.Contains(currentState)
+}
+
+// step returns the state which the Machine moves to from state q on
+// the provided symbol, and false if there is no such transition.
+func (m Machine[T]) step(q int, symbol T) (int, bool) {
+	to, ok := m.D[q][symbol]
+	return to, ok
+}
+
+// Minimize returns the equivalent Machine with the fewest states, as
+// described for Dfa.Minimize. It panics if the Machine has a
+// transition on a symbol outside its alphabet.
+func (m Machine[T]) Minimize() Machine[T] {
+	m.checkAlphabet()
+
+	// Initially, states are divided into accepting and non-accepting
+	// classes.
+	class := make([]int, m.Q)
+	for q := range class {
+		if m.F.Contains(q) {
+			class[q] = 1
+		}
+	}
+
+	result, _ := m.refine(class)
+	return result
+}
+
+// checkAlphabet panics if the Machine has a transition on a symbol
+// outside its alphabet.
+func (m Machine[T]) checkAlphabet() {
+	alphabet := make(map[T]bool, len(m.S))
+	for _, symbol := range m.S {
+		alphabet[symbol] = true
+	}
+	for q, trans := range m.D {
+		for symbol := range trans {
+			if !alphabet[symbol] {
+				panic(fmt.Sprintf("dfa: state %d, symbol %v: symbol not in alphabet", q, symbol))
+			}
+		}
+	}
+}
+
+// Relabel returns a Machine with the same states as the Dfa, in
+// which each transition on the rune i is replaced by a transition on
+// symbols[i]. Its alphabet is symbols. The Dfa must have no
+// transitions on runes outside the range of symbols.
+func Relabel[T comparable](d Dfa, symbols []T) Machine[T] {
+	m := Machine[T]{
+		Q:  d.Q,
+		S:  append([]T{}, symbols...),
+		D:  make([]map[T]int, len(d.D)),
+		Qs: d.Qs,
+		F:  sets.NewSetInt(d.F.Elements()...),
+	}
+	for q, trans := range d.D {
+		m.D[q] = make(map[T]int)
+		for letter, to := range trans {
+			m.D[q][symbols[letter]] = to
+		}
+	}
+	return m
+}
+
+// FromDfa returns the Dfa as a Machine over runes, sharing its
+// transition function and accepting states. The alphabet of the
+// Machine is the Dfa's, together with any other runes on which it
+// has transitions, in ascending order.
+func FromDfa(d Dfa) Machine[rune] {
+	return Machine[rune]{
+		Q:  d.Q,
+		S:  d.alphabet(),
+		D:  d.D,
+		Qs: d.Qs,
+		F:  d.F,
+	}
+}
+
+// ToDfa returns the Machine over runes as a Dfa, sharing its
+// transition function and accepting states.
+func ToDfa(m Machine[rune]) Dfa {
+	return Dfa{
+		Q:  m.Q,
+		S:  sets.NewSetRune(m.S...),
+		D:  m.D,
+		Qs: m.Qs,
+		F:  m.F,
+	}
+}
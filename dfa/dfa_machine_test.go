@@ -0,0 +1,116 @@
+package dfa_test
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/gods/sets"
+	"testing"
+)
+
+type event int
+
+const (
+	connect event = iota
+	send
+	disconnect
+)
+
+// allSequences returns all the sequences of the provided symbols with
+// length at most n.
+func allSequences[T any](symbols []T, n int) [][]T {
+	result := [][]T{{}}
+	last := [][]T{{}}
+	for i := 0; i < n; i++ {
+		next := [][]T{}
+		for _, s := range last {
+			for _, symbol := range symbols {
+				next = append(next, append(append([]T{}, s...), symbol))
+			}
+		}
+		result = append(result, next...)
+		last = next
+	}
+	return result
+}
+
+// session accepts sequences of events which connect, send any number
+// of times, and disconnect. States 1 and 2 are equivalent.
+var session = dfa.Machine[event]{
+	Q: 4,
+	S: []event{connect, send, disconnect},
+	D: []map[event]int{
+		{connect: 1},
+		{send: 2, disconnect: 3},
+		{send: 1, disconnect: 3},
+		{},
+	},
+	Qs: 0,
+	F:  sets.NewSetInt(3),
+}
+
+func TestMachineAccepts(t *testing.T) {
+	testCases := []struct {
+		input []event
+		want  bool
+	}{
+		{[]event{connect, disconnect}, true},
+		{[]event{connect, send, send, send, disconnect}, true},
+		{[]event{connect, send}, false},
+		{[]event{send, disconnect}, false},
+		{[]event{connect, disconnect, connect}, false},
+		{[]event{}, false},
+	}
+
+	for n, tc := range testCases {
+		if got := session.Accepts(tc.input); got != tc.want {
+			t.Errorf("case %d, got %t, want %t", n+1, got, tc.want)
+		}
+	}
+}
+
+func TestMachineMinimize(t *testing.T) {
+	m := session.Minimize()
+	if m.Q != 3 {
+		t.Errorf("got %d states, want 3", m.Q)
+	}
+	for _, s := range allSequences(session.S, 5) {
+		if got, want := m.Accepts(s), session.Accepts(s); got != want {
+			t.Errorf("sequence %v, got %t, want %t", s, got, want)
+		}
+	}
+
+	if got, want := dfa.FromDfa(m1).Minimize(), m1.Minimize(); !equalDfas(dfa.ToDfa(got), want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMachineConversion(t *testing.T) {
+	m := dfa.FromDfa(m1)
+	if got, want := string(m.S), "01"; got != want {
+		t.Errorf("got alphabet %q, want %q", got, want)
+	}
+	for _, s := range allStrings("01", 5) {
+		if got, want := m.Accepts([]rune(s)), m1.Accepts(s); got != want {
+			t.Errorf("string %q, got %t, want %t", s, got, want)
+		}
+	}
+	if !equalDfas(dfa.ToDfa(m), m1) {
+		t.Errorf("round trip changed automaton")
+	}
+}
+
+func TestMachineMinimizeOutsideAlphabet(t *testing.T) {
+	m := dfa.Machine[event]{
+		Q:  2,
+		S:  []event{connect},
+		D:  []map[event]int{{connect: 1, disconnect: 0}, {}},
+		Qs: 0,
+		F:  sets.NewSetInt(1),
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Minimize didn't panic")
+		}
+	}()
+	m.Minimize()
+}
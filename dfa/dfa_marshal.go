@@ -0,0 +1,204 @@
+package dfa
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/paulgriffiths/automata/internal/table"
+	"github.com/paulgriffiths/gods/sets"
+	"unicode/utf8"
+)
+
+// jsonDfa is the JSON representation of a Dfa. Runes are written as
+// single-character strings, and the transitions of each state as an
+// object mapping those strings to states, so that encoding/json
+// writes them in a stable order.
+type jsonDfa struct {
+	States      int              `json:"states"`
+	Alphabet    []string         `json:"alphabet"`
+	Start       int              `json:"start"`
+	Accepting   []int            `json:"accepting"`
+	Transitions []map[string]int `json:"transitions"`
+}
+
+// MarshalJSON returns the JSON encoding of the Dfa.
+func (d Dfa) MarshalJSON() ([]byte, error) {
+	j := jsonDfa{
+		States:      d.Q,
+		Alphabet:    []string{},
+		Start:       d.Qs,
+		Accepting:   d.F.Elements(),
+		Transitions: make([]map[string]int, len(d.D)),
+	}
+
+	for _, letter := range d.alphabet() {
+		j.Alphabet = append(j.Alphabet, string(letter))
+	}
+	for q, trans := range d.D {
+		j.Transitions[q] = make(map[string]int)
+		for letter, to := range trans {
+			j.Transitions[q][string(letter)] = to
+		}
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON sets the Dfa to the one described by the provided
+// JSON encoding.
+// If that Dfa is not well-formed, the error is a *ValidationError.
+func (d *Dfa) UnmarshalJSON(data []byte) error {
+	j := jsonDfa{}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	result := Dfa{
+		Q:  j.States,
+		S:  sets.NewSetRune(),
+		D:  make([]map[rune]int, len(j.Transitions)),
+		Qs: j.Start,
+		F:  sets.NewSetInt(j.Accepting...),
+	}
+
+	for _, s := range j.Alphabet {
+		letter, err := jsonRune(s)
+		if err != nil {
+			return err
+		}
+		result.S.Insert(letter)
+	}
+	for q, trans := range j.Transitions {
+		result.D[q] = make(map[rune]int)
+		for s, to := range trans {
+			letter, err := jsonRune(s)
+			if err != nil {
+				return err
+			}
+			result.D[q][letter] = to
+		}
+	}
+
+	if err := result.Validate(); err != nil {
+		return err
+	}
+
+	*d = result
+	return nil
+}
+
+// jsonRune converts a single-character string to a rune.
+func jsonRune(s string) (rune, error) {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || size != len(s) || r == utf8.RuneError && size == 1 {
+		return 0, fmt.Errorf("dfa: bad rune %q", s)
+	}
+	return r, nil
+}
+
+// MarshalText returns the Dfa as a human-readable state table, for
+// example:
+//
+//	dfa
+//	      '0'  '1'
+//	>  0  0    1
+//	*  1  2    1
+//	   2  1    1
+//
+// The header lists the alphabet, and each row gives a state, marked
+// with > if it is the start state and * if it is accepting, followed
+// by the state moved to on each letter, or - if there is no such
+// transition.
+func (d Dfa) MarshalText() ([]byte, error) {
+	alphabet := d.alphabet()
+	t := table.Table{Kind: "dfa"}
+	for _, letter := range alphabet {
+		t.Header = append(t.Header, table.FormatRune(letter))
+	}
+
+	for q := 0; q < d.Q; q++ {
+		row := table.Row{
+			Start:     q == d.Qs,
+			Accepting: d.F.Contains(q),
+			State:     q,
+		}
+		for _, letter := range alphabet {
+			to, ok := 0, false
+			if q < len(d.D) {
+				to, ok = d.D[q][letter]
+			}
+			row.Cells = append(row.Cells, table.FormatState(to, ok))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	return table.Write(t), nil
+}
+
+// UnmarshalText sets the Dfa to the one described by the provided
+// state table, in the format written by MarshalText. The rows may
+// appear in any order, but there must be exactly one for each state.
+// If the Dfa is not well-formed, the error is a *ValidationError.
+func (d *Dfa) UnmarshalText(text []byte) error {
+	t, err := table.Read(text, "dfa")
+	if err != nil {
+		return fmt.Errorf("dfa: %v", err)
+	}
+
+	result := Dfa{
+		Q:  len(t.Rows),
+		S:  sets.NewSetRune(),
+		D:  make([]map[rune]int, len(t.Rows)),
+		Qs: -1,
+		F:  sets.NewSetInt(),
+	}
+
+	alphabet := []rune{}
+	for _, token := range t.Header {
+		letter, err := table.ParseRune(token)
+		if err != nil {
+			return fmt.Errorf("dfa: %v", err)
+		}
+		alphabet = append(alphabet, letter)
+		result.S.Insert(letter)
+	}
+
+	for _, row := range t.Rows {
+		if row.State < 0 || row.State >= result.Q {
+			return fmt.Errorf("dfa: state %d out of range", row.State)
+		}
+		if result.D[row.State] != nil {
+			return fmt.Errorf("dfa: duplicate state %d", row.State)
+		}
+		if row.Start {
+			if result.Qs != -1 {
+				return fmt.Errorf("dfa: more than one start state")
+			}
+			result.Qs = row.State
+		}
+		if row.Accepting {
+			result.F.Insert(row.State)
+		}
+
+		result.D[row.State] = make(map[rune]int)
+		for i, cell := range row.Cells {
+			to, ok, err := table.ParseState(cell)
+			if err != nil {
+				return fmt.Errorf("dfa: state %d: %v", row.State, err)
+			}
+			if ok {
+				result.D[row.State][alphabet[i]] = to
+			}
+		}
+	}
+
+	if result.Qs == -1 {
+		return fmt.Errorf("dfa: missing start state")
+	}
+
+	if err := result.Validate(); err != nil {
+		return err
+	}
+
+	*d = result
+	return nil
+}
@@ -0,0 +1,183 @@
+package dfa_test
+
+import (
+	"encoding/json"
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/gods/sets"
+	"testing"
+)
+
+/*
+DFA M1 accepts strings that contain at least one 1
+with an even number of 0s following the last 1.
+*/
+var m1 = dfa.Dfa{
+	Q: 3,
+	S: sets.NewSetRune('0', '1'),
+	D: []map[rune]int{
+		{'0': 0, '1': 1},
+		{'0': 2, '1': 1},
+		{'0': 1, '1': 1},
+	},
+	Qs: 0,
+	F:  sets.NewSetInt(1),
+}
+
+// equalDfas returns true if two Dfas have identical definitions.
+func equalDfas(a, b dfa.Dfa) bool {
+	if a.Q != b.Q || a.Qs != b.Qs || !a.F.Equals(b.F) ||
+		!a.S.Equals(b.S) || len(a.D) != len(b.D) {
+		return false
+	}
+	for q := range a.D {
+		if len(a.D[q]) != len(b.D[q]) {
+			return false
+		}
+		for letter, to := range a.D[q] {
+			if other, ok := b.D[q][letter]; !ok || other != to {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestMarshalJSON(t *testing.T) {
+	want := `{"states":3,"alphabet":["0","1"],"start":0,"accepting":[1],` +
+		`"transitions":[{"0":0,"1":1},{"0":2,"1":1},{"0":1,"1":1}]}`
+
+	b, err := json.Marshal(m1)
+	if err != nil {
+		t.Fatalf("couldn't marshal Dfa: %v", err)
+	}
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	var d dfa.Dfa
+	if err := json.Unmarshal(b, &d); err != nil {
+		t.Fatalf("couldn't unmarshal Dfa: %v", err)
+	}
+	if !equalDfas(d, m1) {
+		t.Errorf("got %v, want %v", d, m1)
+	}
+}
+
+func TestUnmarshalJSONErrors(t *testing.T) {
+	testCases := []string{
+		`{"states":1,"alphabet":["ab"],"start":0,"transitions":[{}]}`,
+		`{"states":1,"alphabet":["a"],"start":0,"transitions":[{"":0}]}`,
+		`{"states":"one"}`,
+		`[`,
+	}
+
+	for n, tc := range testCases {
+		var d dfa.Dfa
+		if err := json.Unmarshal([]byte(tc), &d); err == nil {
+			t.Errorf("case %d, unexpectedly unmarshaled %s", n+1, tc)
+		}
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	want := `dfa
+      '0'  '1'
+>  0  0    1
+*  1  2    1
+   2  1    1
+`
+
+	b, err := m1.MarshalText()
+	if err != nil {
+		t.Fatalf("couldn't marshal Dfa: %v", err)
+	}
+	if string(b) != want {
+		t.Errorf("got\n%s\nwant\n%s", b, want)
+	}
+
+	var d dfa.Dfa
+	if err := d.UnmarshalText(b); err != nil {
+		t.Fatalf("couldn't unmarshal Dfa: %v", err)
+	}
+	if !equalDfas(d, m1) {
+		t.Errorf("got %v, want %v", d, m1)
+	}
+}
+
+func TestMarshalTextEmptyAlphabet(t *testing.T) {
+	want := dfa.Dfa{
+		Q:  1,
+		S:  sets.NewSetRune(),
+		D:  []map[rune]int{{}},
+		Qs: 0,
+		F:  sets.NewSetInt(0),
+	}
+
+	b, err := want.MarshalText()
+	if err != nil {
+		t.Fatalf("couldn't marshal Dfa: %v", err)
+	}
+	var d dfa.Dfa
+	if err := d.UnmarshalText(b); err != nil {
+		t.Fatalf("couldn't unmarshal %q: %v", b, err)
+	}
+	if !equalDfas(d, want) {
+		t.Errorf("got %v, want %v", d, want)
+	}
+}
+
+func TestUnmarshalText(t *testing.T) {
+	text := `# Accepts strings ending in a space, with rows out of order.
+dfa
+       'a'  ' '  '\''
+*  1   0    1    -
+>  0   0    1    0
+`
+	var d dfa.Dfa
+	if err := d.UnmarshalText([]byte(text)); err != nil {
+		t.Fatalf("couldn't unmarshal Dfa: %v", err)
+	}
+
+	testCases := []struct {
+		input  string
+		result bool
+	}{
+		{"", false},
+		{"a", false},
+		{"a ", true},
+		{"a'", false},
+		{"' ", true},
+		{"  ", true},
+		{" '", false},
+		{" 'a", false},
+	}
+
+	for _, tc := range testCases {
+		if r := d.Accepts(tc.input); r != tc.result {
+			t.Errorf("input %q, got %v, want %v", tc.input, r, tc.result)
+		}
+	}
+}
+
+func TestUnmarshalTextErrors(t *testing.T) {
+	testCases := []string{
+		"",
+		"nfa\n'a'\n>0 0\n",
+		"dfa\n",
+		"dfa\n'a'\n0 0\n",
+		"dfa\n'a'\n>0 0\n>1 0\n",
+		"dfa\n'a'\n>0 0\n0 0\n",
+		"dfa\n'a'\n>0 0 1\n",
+		"dfa\n'a'\n>0 x\n",
+		"dfa\n'a'\n>2 0\n",
+		"dfa\na\n>0 0\n",
+		"dfa\n'a\n>0 0\n",
+	}
+
+	for n, tc := range testCases {
+		var d dfa.Dfa
+		if err := d.UnmarshalText([]byte(tc)); err == nil {
+			t.Errorf("case %d, unexpectedly unmarshaled %q", n+1, tc)
+		}
+	}
+}
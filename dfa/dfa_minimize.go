@@ -0,0 +1,179 @@
+package dfa
+
+import (
+	"github.com/paulgriffiths/gods/sets"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Minimize returns the equivalent Dfa with the fewest states. States
+// which cannot be reached from the start state are removed, and the
+// remaining states are merged by partition refinement until no two
+// states accept the same set of strings. States from which no string
+// can be accepted are removed along with any transitions to them,
+// since a missing transition already causes the Dfa to reject. The
+// states of the result are numbered in breadth-first order from the
+// start state, which is state 0.
+func (d Dfa) Minimize() Dfa {
+	m := FromDfa(d).Minimize()
+	return Dfa{Q: m.Q, S: d.S, D: m.D, Qs: m.Qs, F: m.F}
+}
+
+// refine minimizes the Dfa as described for Minimize, starting from
+// the provided initial partition of its states into classes. States
+// in different initial classes are never merged, so the initial
+// partition must at least separate accepting states from
+// non-accepting states. As well as the minimized Dfa, it returns
+// for each state of the result a state of the original Dfa which it
+// represents.
+func (d Dfa) refine(class []int) (Dfa, []int) {
+	m, reps := FromDfa(d).refine(class)
+	return Dfa{Q: m.Q, S: d.S, D: m.D, Qs: m.Qs, F: m.F}, reps
+}
+
+// refine minimizes the Machine as described for Dfa.refine,
+// considering only transitions on the symbols of its alphabet.
+func (m Machine[T]) refine(class []int) (Machine[T], []int) {
+	// Class -1 is the implicit dead state reached on any missing
+	// transition.
+	for {
+		signatures := make(map[string]int)
+		next := make([]int, m.Q)
+		for q := range next {
+			sig := m.signature(q, class)
+			n, ok := signatures[sig]
+			if !ok {
+				n = len(signatures)
+				signatures[sig] = n
+			}
+			next[q] = n
+		}
+		stable := len(signatures) == countClasses(class)
+		class = next
+		if stable {
+			break
+		}
+	}
+
+	// A class is live if it contains an accepting state, or if it
+	// has a transition to a live class. All other classes are dead.
+	live := make(map[int]bool)
+	for _, q := range m.F.Elements() {
+		live[class[q]] = true
+	}
+	for changed := true; changed; {
+		changed = false
+		for q := 0; q < m.Q; q++ {
+			if live[class[q]] {
+				continue
+			}
+			for _, symbol := range m.S {
+				if to, ok := m.D[q][symbol]; ok && live[class[to]] {
+					live[class[q]] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	dead := make(map[int]bool)
+	for _, c := range class {
+		dead[c] = !live[c]
+	}
+
+	return m.quotient(class, dead)
+}
+
+// quotient returns the Machine whose states are the live classes of
+// states reachable from the start state, and a representative state
+// of the original Machine for each of them.
+func (m Machine[T]) quotient(class []int, dead map[int]bool) (Machine[T], []int) {
+	representative := make(map[int]int)
+	for q := m.Q - 1; q >= 0; q-- {
+		representative[class[q]] = q
+	}
+
+	number := map[int]int{class[m.Qs]: 0}
+	order := []int{class[m.Qs]}
+	tfunc := []map[T]int{}
+	accepts := sets.NewSetInt()
+	reps := []int{}
+
+	for i := 0; i < len(order); i++ {
+		trans := make(map[T]int)
+		q := representative[order[i]]
+		reps = append(reps, q)
+		if dead[order[i]] {
+			tfunc = append(tfunc, trans)
+			continue
+		}
+		if m.F.Contains(q) {
+			accepts.Insert(i)
+		}
+		for _, symbol := range m.S {
+			to, ok := m.D[q][symbol]
+			if !ok || dead[class[to]] {
+				continue
+			}
+			j, ok := number[class[to]]
+			if !ok {
+				j = len(order)
+				number[class[to]] = j
+				order = append(order, class[to])
+			}
+			trans[symbol] = j
+		}
+		tfunc = append(tfunc, trans)
+	}
+
+	return Machine[T]{
+		Q:  len(order),
+		S:  append([]T{}, m.S...),
+		D:  tfunc,
+		Qs: 0,
+		F:  accepts,
+	}, reps
+}
+
+// signature returns a string identifying the class of state q and
+// the classes of the states it moves to on each symbol.
+func (m Machine[T]) signature(q int, class []int) string {
+	var b strings.Builder
+	b.WriteString(strconv.Itoa(class[q]))
+	for _, symbol := range m.S {
+		b.WriteByte(',')
+		if to, ok := m.D[q][symbol]; ok {
+			b.WriteString(strconv.Itoa(class[to]))
+		} else {
+			b.WriteString("-1")
+		}
+	}
+	return b.String()
+}
+
+// alphabet returns, in ascending order, the letters of the alphabet
+// together with any other runes on which the Dfa has transitions.
+func (d Dfa) alphabet() []rune {
+	letters := sets.NewSetRune(d.S.Elements()...)
+	for _, trans := range d.D {
+		for letter := range trans {
+			letters.Insert(letter)
+		}
+	}
+	alphabet := letters.Elements()
+	sort.Slice(alphabet, func(i, j int) bool {
+		return alphabet[i] < alphabet[j]
+	})
+	return alphabet
+}
+
+// countClasses returns the number of distinct classes in a partition.
+func countClasses(class []int) int {
+	distinct := make(map[int]bool)
+	for _, c := range class {
+		distinct[c] = true
+	}
+	return len(distinct)
+}
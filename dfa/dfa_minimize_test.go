@@ -0,0 +1,110 @@
+package dfa_test
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/gods/sets"
+	"testing"
+)
+
+// allStrings returns all strings over the provided alphabet with
+// lengths up to and including n.
+func allStrings(alphabet string, n int) []string {
+	result := []string{""}
+	last := []string{""}
+	for i := 0; i < n; i++ {
+		next := []string{}
+		for _, s := range last {
+			for _, r := range alphabet {
+				next = append(next, s+string(r))
+			}
+		}
+		result = append(result, next...)
+		last = next
+	}
+	return result
+}
+
+func TestMinimize(t *testing.T) {
+	testCases := []struct {
+		d      dfa.Dfa
+		states int
+	}{
+		// Accepts strings ending in 1, with a redundant copy of
+		// each state and an unreachable state.
+		{
+			dfa.Dfa{
+				Q: 5,
+				S: sets.NewSetRune('0', '1'),
+				D: []map[rune]int{
+					{'0': 2, '1': 1},
+					{'0': 2, '1': 3},
+					{'0': 0, '1': 3},
+					{'0': 0, '1': 1},
+					{'0': 4, '1': 4},
+				},
+				Qs: 0,
+				F:  sets.NewSetInt(1, 3),
+			},
+			2,
+		},
+		// Accepts strings that start and end with the same letter.
+		{
+			dfa.Dfa{
+				Q: 5,
+				S: sets.NewSetRune('a', 'b'),
+				D: []map[rune]int{
+					{'a': 1, 'b': 3},
+					{'a': 1, 'b': 2},
+					{'a': 1, 'b': 2},
+					{'a': 4, 'b': 3},
+					{'a': 4, 'b': 3},
+				},
+				Qs: 0,
+				F:  sets.NewSetInt(1, 3),
+			},
+			5,
+		},
+		// Accepts ab, with a dead state and a state from which no
+		// accepting state can be reached.
+		{
+			dfa.Dfa{
+				Q: 5,
+				S: sets.NewSetRune('a', 'b'),
+				D: []map[rune]int{
+					{'a': 1, 'b': 3},
+					{'a': 3, 'b': 2},
+					{'a': 3, 'b': 3},
+					{'a': 3, 'b': 4},
+					{'a': 4},
+				},
+				Qs: 0,
+				F:  sets.NewSetInt(2),
+			},
+			3,
+		},
+		// Accepts nothing.
+		{
+			dfa.Dfa{
+				Q:  2,
+				S:  sets.NewSetRune('a'),
+				D:  []map[rune]int{{'a': 1}, {'a': 0}},
+				Qs: 0,
+				F:  sets.NewSetInt(),
+			},
+			1,
+		},
+	}
+
+	for n, tc := range testCases {
+		m := tc.d.Minimize()
+		if m.Q != tc.states {
+			t.Errorf("case %d, got %d states, want %d", n+1, m.Q, tc.states)
+		}
+		for _, s := range allStrings("ab01", 5) {
+			if got, want := m.Accepts(s), tc.d.Accepts(s); got != want {
+				t.Errorf("case %d, input %q, got %t, want %t",
+					n+1, s, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,157 @@
+package dfa
+
+import (
+	"github.com/paulgriffiths/gods/sets"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RuneClasses partitions the alphabet of a Dfa into equivalence
+// classes of runes on which every state has the same transition, so
+// that a transition table need only have one column for each class.
+// The classes are numbered from 0 in order of their smallest runes.
+type RuneClasses struct {
+	ascii [utf8.RuneSelf]int // Class of each ASCII rune plus one, or 0
+	other map[rune]int       // Class of each other rune
+	runes [][]rune           // Runes in each class, in ascending order
+}
+
+// Class returns the class of the provided rune, and false if the
+// rune is not in the alphabet.
+func (c RuneClasses) Class(r rune) (int, bool) {
+	if r >= 0 && r < utf8.RuneSelf {
+		return c.ascii[r] - 1, c.ascii[r] != 0
+	}
+	class, ok := c.other[r]
+	return class, ok
+}
+
+// Len returns the number of classes.
+func (c RuneClasses) Len() int {
+	return len(c.runes)
+}
+
+// Runes returns, in ascending order, the runes in the provided class.
+func (c RuneClasses) Runes(class int) []rune {
+	return append([]rune{}, c.runes[class]...)
+}
+
+// RuneClasses partitions the alphabet of the Dfa into classes of
+// runes on which every state has the same transition, or none.
+func (d Dfa) RuneClasses() RuneClasses {
+	c := RuneClasses{other: make(map[rune]int)}
+	signatures := make(map[string]int)
+
+	for _, letter := range d.alphabet() {
+		var b strings.Builder
+		for q := range d.D {
+			to, ok := d.D[q][letter]
+			if !ok {
+				to = -1
+			}
+			b.WriteString(strconv.Itoa(to))
+			b.WriteByte(',')
+		}
+
+		class, ok := signatures[b.String()]
+		if !ok {
+			class = len(c.runes)
+			signatures[b.String()] = class
+			c.runes = append(c.runes, nil)
+		}
+		c.runes[class] = append(c.runes[class], letter)
+		if letter >= 0 && letter < utf8.RuneSelf {
+			c.ascii[letter] = class + 1
+		} else {
+			c.other[letter] = class
+		}
+	}
+
+	return c
+}
+
+// Table is a deterministic finite automaton with a dense transition
+// table indexed by state and rune class, which needs far less memory
+// than the maps of a Dfa when its alphabet is large, and is faster to
+// run.
+type Table struct {
+	Classes RuneClasses // Classes of the alphabet
+	T       [][]int     // Transition table, -1 for no transition
+	Qs      int         // Start state
+	F       []bool      // Whether each state is accepting
+}
+
+// ToTable converts the Dfa to a Table with one column for each of
+// its rune classes.
+func (d Dfa) ToTable() Table {
+	t := Table{
+		Classes: d.RuneClasses(),
+		T:       make([][]int, d.Q),
+		Qs:      d.Qs,
+		F:       make([]bool, d.Q),
+	}
+
+	for q := 0; q < d.Q; q++ {
+		t.T[q] = make([]int, t.Classes.Len())
+		for class := range t.T[q] {
+			to, ok := d.D[q][t.Classes.runes[class][0]]
+			if !ok {
+				to = -1
+			}
+			t.T[q][class] = to
+		}
+		t.F[q] = d.F.Contains(q)
+	}
+
+	return t
+}
+
+// Accepts returns true if the Table accepts the provided string.
+func (t Table) Accepts(input string) bool {
+	currentState := t.Qs
+
+	for _, letter := range input {
+		class, ok := t.Classes.Class(letter)
+		if !ok {
+			return false
+		}
+		currentState = t.T[currentState][class]
+		if currentState == -1 {
+			return false
+		}
+	}
+
+	return t.F[currentState]
+}
+
+// ToDfa converts the Table back to a Dfa.
+func (t Table) ToDfa() Dfa {
+	d := Dfa{
+		Q:  len(t.T),
+		S:  sets.NewSetRune(),
+		D:  make([]map[rune]int, len(t.T)),
+		Qs: t.Qs,
+		F:  sets.NewSetInt(),
+	}
+
+	for _, runes := range t.Classes.runes {
+		d.S.Insert(runes...)
+	}
+	for q, row := range t.T {
+		d.D[q] = make(map[rune]int)
+		for class, to := range row {
+			if to == -1 {
+				continue
+			}
+			for _, letter := range t.Classes.runes[class] {
+				d.D[q][letter] = to
+			}
+		}
+		if t.F[q] {
+			d.F.Insert(q)
+		}
+	}
+
+	return d
+}
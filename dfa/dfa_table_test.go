@@ -0,0 +1,118 @@
+package dfa_test
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// identifier returns a Dfa accepting identifiers made of the letters
+// in the provided range table, the underscore, and the ASCII digits,
+// not starting with a digit.
+func identifier(letters *unicode.RangeTable) dfa.Dfa {
+	b := dfa.NewBuilder()
+	start, ident := b.AddState(), b.AddState()
+	for _, r16 := range letters.R16 {
+		for r := rune(r16.Lo); r <= rune(r16.Hi); r += rune(r16.Stride) {
+			b.AddTransition(start, r, ident)
+			b.AddTransition(ident, r, ident)
+		}
+	}
+	for _, r32 := range letters.R32 {
+		for r := rune(r32.Lo); r <= rune(r32.Hi); r += rune(r32.Stride) {
+			b.AddTransition(start, r, ident)
+			b.AddTransition(ident, r, ident)
+		}
+	}
+	b.AddTransition(start, '_', ident)
+	b.AddTransition(ident, '_', ident)
+	for r := '0'; r <= '9'; r++ {
+		b.AddTransition(ident, r, ident)
+	}
+	b.SetAccepting(ident)
+
+	d, err := b.Build()
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestRuneClasses(t *testing.T) {
+	c := identifier(unicode.Greek).RuneClasses()
+	if c.Len() != 2 {
+		t.Fatalf("got %d classes, want 2", c.Len())
+	}
+	if got, want := c.Runes(0), []rune("0123456789"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got runes %q, want %q", got, want)
+	}
+
+	testCases := []struct {
+		r     rune
+		class int
+		ok    bool
+	}{
+		{'0', 0, true},
+		{'9', 0, true},
+		{'_', 1, true},
+		{'α', 1, true},
+		{'a', 0, false},
+		{'€', 0, false},
+		{-1, 0, false},
+	}
+
+	for n, tc := range testCases {
+		class, ok := c.Class(tc.r)
+		if ok != tc.ok || ok && class != tc.class {
+			t.Errorf("case %d, got %d, %t, want %d, %t",
+				n+1, class, ok, tc.class, tc.ok)
+		}
+	}
+
+	m1Classes := m1.RuneClasses()
+	if m1Classes.Len() != 2 {
+		t.Errorf("got %d classes, want 2", m1Classes.Len())
+	}
+}
+
+func TestTable(t *testing.T) {
+	testCases := []dfa.Dfa{
+		m1,
+		identifier(unicode.Greek),
+		dfa.FromKeywords([]string{"ab", "ba", "abba", "c"}),
+		dfa.FromKeywords([]string{}),
+	}
+
+	for n, d := range testCases {
+		table := d.ToTable()
+		for _, s := range allStrings("01abc_α", 4) {
+			if got, want := table.Accepts(s), d.Accepts(s); got != want {
+				t.Errorf("case %d, string %q, got %t, want %t", n+1, s, got, want)
+			}
+		}
+		if !equalDfas(table.ToDfa(), d) {
+			t.Errorf("case %d, round trip changed automaton", n+1)
+		}
+	}
+}
+
+func BenchmarkAccepts(b *testing.B) {
+	d := identifier(unicode.Letter)
+	table := d.ToTable()
+	input := strings.Repeat("identifier_ßλ0", 100)
+
+	b.Run("Map", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			d.Accepts(input)
+		}
+	})
+	b.Run("Table", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			table.Accepts(input)
+		}
+	})
+}
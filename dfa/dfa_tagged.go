@@ -0,0 +1,113 @@
+package dfa
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/gods/sets"
+	"strings"
+)
+
+// TaggedDfa is a Dfa in which each state is labeled with a set of
+// tags, for example the indices of the patterns in a set of regular
+// expressions which are matched on reaching that state. The states
+// with tags should be exactly the accepting states.
+type TaggedDfa struct {
+	Dfa
+	T []sets.SetInt // Tags of each state, may be nil if none
+}
+
+// Tags returns, in ascending order, the tags of the state reached
+// on the provided string, or nil if it has no tags or if the
+// TaggedDfa has no transition on some letter of the string.
+func (t TaggedDfa) Tags(input string) []int {
+	currentState := t.Qs
+	ok := false
+
+	for _, letter := range input {
+		currentState, ok = t.D[currentState][letter]
+		if !ok {
+			return nil
+		}
+	}
+
+	if currentState >= len(t.T) || t.T[currentState].IsEmpty() {
+		return nil
+	}
+	return t.T[currentState].Elements()
+}
+
+// Minimize returns the equivalent TaggedDfa with the fewest states,
+// as described for Dfa.Minimize, except that two states are only
+// merged if they have the same tags.
+func (t TaggedDfa) Minimize() TaggedDfa {
+	// Initially, states are divided into classes by whether they are
+	// accepting and by their tags.
+	keys := make(map[string]int)
+	class := make([]int, t.Q)
+	for q := range class {
+		key := fmt.Sprint(t.F.Contains(q))
+		if q < len(t.T) {
+			key += fmt.Sprint(t.T[q].Elements())
+		}
+		n, ok := keys[key]
+		if !ok {
+			n = len(keys)
+			keys[key] = n
+		}
+		class[q] = n
+	}
+
+	m, reps := t.Dfa.refine(class)
+	tags := make([]sets.SetInt, m.Q)
+	for i, q := range reps {
+		tags[i] = sets.NewSetInt()
+		if q < len(t.T) {
+			tags[i].Merge(t.T[q])
+		}
+	}
+
+	return TaggedDfa{m, tags}
+}
+
+// Validate checks that the TaggedDfa is well-formed, that is, that
+// its Dfa is well-formed as described for Dfa.Validate, that it has
+// no more tag sets than states, and that its states with tags are
+// exactly its accepting states. If it is not, the returned error is a
+// *ValidationError describing every problem found, otherwise it is
+// nil.
+func (t TaggedDfa) Validate() error {
+	problems := []string{}
+	if err := t.Dfa.Validate(); err != nil {
+		problems = append(problems, err.(*ValidationError).Problems...)
+	}
+
+	if len(t.T) > t.Q {
+		problems = append(problems, fmt.Sprintf(
+			"got %d tag sets, want at most %d", len(t.T), t.Q))
+	}
+	for q := 0; q < t.Q; q++ {
+		tagged := q < len(t.T) && !t.T[q].IsEmpty()
+		switch accepting := t.F.Contains(q); {
+		case accepting && !tagged:
+			problems = append(problems, fmt.Sprintf(
+				"state %d: accepting state has no tags", q))
+		case !accepting && tagged:
+			problems = append(problems, fmt.Sprintf(
+				"state %d: non-accepting state has tags %s",
+				q, formatTags(t.T[q])))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{problems}
+	}
+	return nil
+}
+
+// formatTags returns a set of tags as a string such as {1,2}.
+func formatTags(tags sets.SetInt) string {
+	elems := []string{}
+	for _, tag := range tags.Elements() {
+		elems = append(elems, fmt.Sprint(tag))
+	}
+	return "{" + strings.Join(elems, ",") + "}"
+}
@@ -0,0 +1,98 @@
+package dfa_test
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/gods/sets"
+	"reflect"
+	"testing"
+)
+
+// Recognizes strings over {a, b} ending in a, tagged 1, or ending in
+// b, tagged 2, with redundant states 1 and 3, and 2 and 4.
+var tagged = dfa.TaggedDfa{
+	Dfa: dfa.Dfa{
+		Q: 5,
+		S: sets.NewSetRune('a', 'b'),
+		D: []map[rune]int{
+			{'a': 1, 'b': 2},
+			{'a': 3, 'b': 2},
+			{'a': 1, 'b': 4},
+			{'a': 3, 'b': 4},
+			{'a': 1, 'b': 2},
+		},
+		Qs: 0,
+		F:  sets.NewSetInt(1, 2, 3, 4),
+	},
+	T: []sets.SetInt{
+		sets.NewSetInt(),
+		sets.NewSetInt(1),
+		sets.NewSetInt(2),
+		sets.NewSetInt(1),
+		sets.NewSetInt(2),
+	},
+}
+
+func TestTaggedDfaTags(t *testing.T) {
+	testCases := []struct {
+		input string
+		tags  []int
+	}{
+		{"", nil},
+		{"a", []int{1}},
+		{"b", []int{2}},
+		{"abba", []int{1}},
+		{"aabab", []int{2}},
+		{"abc", nil},
+	}
+
+	for n, tc := range []dfa.TaggedDfa{tagged, tagged.Minimize()} {
+		for _, c := range testCases {
+			if tags := tc.Tags(c.input); !reflect.DeepEqual(tags, c.tags) {
+				t.Errorf("case %d, input %q, got %v, want %v",
+					n+1, c.input, tags, c.tags)
+			}
+		}
+	}
+}
+
+func TestTaggedDfaMinimize(t *testing.T) {
+	m := tagged.Minimize()
+	if m.Q != 3 {
+		t.Errorf("got %d states, want 3", m.Q)
+	}
+	if err := m.Validate(); err != nil {
+		t.Errorf("invalid TaggedDfa: %v", err)
+	}
+
+	// Without tags, states 1 and 2 would be merged.
+	if q := tagged.Dfa.Minimize().Q; q != 2 {
+		t.Errorf("got %d states, want 2", q)
+	}
+}
+
+func TestTaggedDfaValidate(t *testing.T) {
+	bad := dfa.TaggedDfa{
+		Dfa: tagged.Dfa,
+		T: []sets.SetInt{
+			sets.NewSetInt(3),
+			sets.NewSetInt(),
+		},
+	}
+
+	want := []string{
+		"state 0: non-accepting state has tags {3}",
+		"state 1: accepting state has no tags",
+		"state 2: accepting state has no tags",
+		"state 3: accepting state has no tags",
+		"state 4: accepting state has no tags",
+	}
+
+	err := bad.Validate()
+	verr, ok := err.(*dfa.ValidationError)
+	if !ok {
+		t.Fatalf("got %v, want *ValidationError", err)
+	}
+	if !reflect.DeepEqual(verr.Problems, want) {
+		t.Errorf("got %q, want %q", verr.Problems, want)
+	}
+}
@@ -0,0 +1,26 @@
+package dfa
+
+import "github.com/paulgriffiths/automata/internal/gnfa"
+
+// ToRegex returns a regular expression matching exactly the strings
+// accepted by the Dfa, computed by the state elimination algorithm,
+// and true. The result uses the syntax accepted by package regex,
+// which cannot express the empty language, nor the empty string
+// other than through a closure, nor any rune other than a letter or
+// a digit. If the language of the Dfa requires any of them, ToRegex
+// returns the empty string and false. The alphabet used is that of
+// the Dfa together with any other runes on which it has transitions.
+// Minimizing the Dfa first usually gives a shorter regular
+// expression.
+func (d Dfa) ToRegex() (string, bool) {
+	alphabet := d.alphabet()
+	g := gnfa.New(d.Q, d.Qs, d.F.Elements())
+	for from, trans := range d.D {
+		for _, letter := range alphabet {
+			if to, ok := trans[letter]; ok {
+				g.AddRune(from, to, letter)
+			}
+		}
+	}
+	return gnfa.String(g.Regex())
+}
@@ -0,0 +1,128 @@
+package dfa_test
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/automata/regex"
+	"github.com/paulgriffiths/gods/sets"
+	"testing"
+)
+
+func TestToRegexRoundTrip(t *testing.T) {
+	testCases := []string{
+		"a",
+		"ab",
+		"a*",
+		"aa*",
+		"a*b|b*",
+		"a|b",
+		"ab|ac",
+		"a*b*",
+		"(a|b)*abb",
+		"aa(a|b)*bb",
+		"((aa|bb)(aa|bb))*",
+		"(a|ab)(c|bcd)",
+		"((a|b)(c|d))*",
+		"a(ba)*|b(ab)*",
+	}
+
+	for n, tc := range testCases {
+		r := regex.Compile(tc)
+		s, ok := r.Dfa().Minimize().ToRegex()
+		if !ok {
+			t.Errorf("case %d, couldn't convert %q", n+1, tc)
+			continue
+		}
+		back := regex.Compile(s)
+		if back == nil {
+			t.Errorf("case %d, couldn't compile %q from %q", n+1, s, tc)
+			continue
+		}
+		for _, input := range allStrings("abcd", 5) {
+			if got, want := back.Match(input), r.Match(input); got != want {
+				t.Errorf("case %d, %q from %q, input %q, got %t, want %t",
+					n+1, s, tc, input, got, want)
+			}
+		}
+	}
+}
+
+func TestToRegex(t *testing.T) {
+	testCases := []struct {
+		pattern, want string
+	}{
+		{"a", "a"},
+		{"abc", "abc"},
+		{"a*", "a*"},
+		{"aa*", "aa*"},
+		{"a*a", "aa*"},
+		{"a|aa*", "aa*"},
+		{"ab*|b*", "ab*|b*"},
+		{"a|b|c", "a|b|c"},
+		{"abc|abd", "ab(c|d)"},
+		{"(a|b)*", "(a|b)*"},
+	}
+
+	for n, tc := range testCases {
+		got, ok := regex.Compile(tc.pattern).Dfa().Minimize().ToRegex()
+		if !ok || got != tc.want {
+			t.Errorf("case %d, got %q, %t, want %q, true", n+1, got, ok, tc.want)
+		}
+	}
+}
+
+func TestToRegexInexpressible(t *testing.T) {
+	testCases := []dfa.Dfa{
+		// The empty language.
+		{
+			Q:  1,
+			S:  sets.NewSetRune('a'),
+			D:  []map[rune]int{{}},
+			Qs: 0,
+			F:  sets.NewSetInt(),
+		},
+		// The language containing only the empty string.
+		{
+			Q:  1,
+			S:  sets.NewSetRune('a'),
+			D:  []map[rune]int{{}},
+			Qs: 0,
+			F:  sets.NewSetInt(0),
+		},
+		// The empty string or a.
+		{
+			Q:  2,
+			S:  sets.NewSetRune('a'),
+			D:  []map[rune]int{{'a': 1}, {}},
+			Qs: 0,
+			F:  sets.NewSetInt(0, 1),
+		},
+		// A rune which is not a letter or a digit.
+		{
+			Q:  2,
+			S:  sets.NewSetRune('('),
+			D:  []map[rune]int{{'(': 1}, {}},
+			Qs: 0,
+			F:  sets.NewSetInt(1),
+		},
+	}
+
+	for n, tc := range testCases {
+		if got, ok := tc.ToRegex(); ok || got != "" {
+			t.Errorf("case %d, got %q, %t, want %q, false", n+1, got, ok, "")
+		}
+	}
+}
+
+func TestToRegexAlphabet(t *testing.T) {
+	// The transition on b is outside the alphabet.
+	d := dfa.Dfa{
+		Q:  2,
+		S:  sets.NewSetRune('a'),
+		D:  []map[rune]int{{'a': 1, 'b': 1}, {}},
+		Qs: 0,
+		F:  sets.NewSetInt(1),
+	}
+	if got, ok := d.ToRegex(); !ok || got != "a|b" {
+		t.Errorf("got %q, %t, want %q, true", got, ok, "a|b")
+	}
+}
@@ -0,0 +1,80 @@
+package dfa
+
+import (
+	"github.com/paulgriffiths/gods/sets"
+	"sort"
+	"unicode/utf8"
+)
+
+// ToUTF8 converts the Dfa to an equivalent byte-level Dfa, whose
+// alphabet consists of the runes 0 to 255 standing for single bytes,
+// and which accepts the UTF-8 encodings of the strings the Dfa
+// accepts. Each transition on a rune is expanded into a chain of
+// transitions on the bytes of its encoding, with chains from the same
+// state sharing their common prefixes, so the result is deterministic.
+// Byte sequences which are not valid UTF-8 have no transitions, so
+// are never accepted, not even by a Dfa with a transition on
+// utf8.RuneError. Runes which cannot be encoded, such as surrogate
+// halves, are dropped. The result is not minimized.
+func (d Dfa) ToUTF8() Dfa {
+	result := Dfa{
+		Q:  d.Q,
+		S:  sets.NewSetRune(),
+		D:  make([]map[rune]int, d.Q),
+		Qs: d.Qs,
+		F:  sets.NewSetInt(d.F.Elements()...),
+	}
+	for q := range result.D {
+		result.D[q] = make(map[rune]int)
+	}
+
+	buf := make([]byte, utf8.UTFMax)
+	for q := 0; q < d.Q && q < len(d.D); q++ {
+		letters := []rune{}
+		for letter := range d.D[q] {
+			letters = append(letters, letter)
+		}
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+
+		for _, letter := range letters {
+			if !utf8.ValidRune(letter) {
+				continue
+			}
+			n := utf8.EncodeRune(buf, letter)
+
+			state := q
+			for _, b := range buf[:n-1] {
+				next, ok := result.D[state][rune(b)]
+				if !ok {
+					next = result.Q
+					result.D = append(result.D, make(map[rune]int))
+					result.Q++
+					result.D[state][rune(b)] = next
+					result.S.Insert(rune(b))
+				}
+				state = next
+			}
+			result.D[state][rune(buf[n-1])] = d.D[q][letter]
+			result.S.Insert(rune(buf[n-1]))
+		}
+	}
+
+	return result
+}
+
+// AcceptsBytes returns true if the Dfa accepts the provided bytes,
+// each of which is taken as the rune with the same value, as for the
+// byte-level automata returned by ToUTF8.
+func (d Dfa) AcceptsBytes(input []byte) bool {
+	currentState := d.Qs
+	ok := false
+
+	for _, b := range input {
+		currentState, ok = d.D[currentState][rune(b)]
+		if !ok {
+			return false
+		}
+	}
+
+	return d.F.Contains(currentState)
+}
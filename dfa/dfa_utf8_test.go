@@ -0,0 +1,73 @@
+package dfa_test
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+func TestToUTF8(t *testing.T) {
+	testCases := []struct {
+		d      dfa.Dfa
+		states int
+	}{
+		{m1, 3},
+		{dfa.FromKeywords([]string{"ñu", "ñe", "€", "a"}), 9},
+		{identifier(unicode.Greek), 0},
+	}
+
+	for n, tc := range testCases {
+		u := tc.d.ToUTF8()
+		if err := u.Validate(); err != nil {
+			t.Errorf("case %d, invalid automaton: %v", n+1, err)
+			continue
+		}
+		if tc.states != 0 && u.Q != tc.states {
+			t.Errorf("case %d, got %d states, want %d", n+1, u.Q, tc.states)
+		}
+		for _, letter := range u.S.Elements() {
+			if letter < 0 || letter > 255 {
+				t.Errorf("case %d, rune %q in byte alphabet", n+1, letter)
+			}
+		}
+		for _, s := range allStrings("01a_ñeuα€", 3) {
+			if got, want := u.AcceptsBytes([]byte(s)), tc.d.Accepts(s); got != want {
+				t.Errorf("case %d, string %q, got %t, want %t", n+1, s, got, want)
+			}
+		}
+	}
+}
+
+func TestToUTF8InvalidInput(t *testing.T) {
+	b := dfa.NewBuilder()
+	q0, q1 := b.AddState(), b.AddState()
+	b.AddTransition(q0, utf8.RuneError, q1)
+	b.AddTransition(q0, 0xD800, q1)
+	b.SetAccepting(q1)
+	d, err := b.Build()
+	if err != nil {
+		t.Fatalf("couldn't build automaton: %v", err)
+	}
+	u := d.ToUTF8()
+
+	testCases := []struct {
+		input []byte
+		want  bool
+	}{
+		{[]byte("�"), true},
+		{[]byte{0xff}, false},
+		{[]byte{0xef, 0xbf}, false},
+		{[]byte{0xed, 0xa0, 0x80}, false},
+		{[]byte{}, false},
+	}
+
+	for n, tc := range testCases {
+		if got := u.AcceptsBytes(tc.input); got != tc.want {
+			t.Errorf("case %d, got %t, want %t", n+1, got, tc.want)
+		}
+	}
+	if !d.Accepts("\xff") {
+		t.Errorf("rune-level automaton doesn't accept invalid UTF-8")
+	}
+}
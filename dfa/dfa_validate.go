@@ -0,0 +1,69 @@
+package dfa
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError describes the structural problems found in a Dfa
+// by Validate.
+type ValidationError struct {
+	Problems []string // Descriptions of each problem found
+}
+
+// Error returns a description of every problem found.
+func (e *ValidationError) Error() string {
+	return "dfa: invalid automaton: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks that the Dfa is well-formed, that is, that it has
+// a transition map for each of its states, that its start state,
+// accepting states and the targets of its transitions are all states
+// of the Dfa, and that every rune with a transition is in its
+// alphabet. If it is not, the returned error is a *ValidationError
+// describing every problem found, otherwise it is nil.
+func (d Dfa) Validate() error {
+	problems := []string{}
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if d.Q < 0 {
+		addProblem("negative number of states %d", d.Q)
+	}
+	if len(d.D) != d.Q {
+		addProblem("got %d transition maps, want %d", len(d.D), d.Q)
+	}
+	if d.Qs < 0 || d.Qs >= d.Q {
+		addProblem("start state %d out of range", d.Qs)
+	}
+	for _, q := range d.F.Elements() {
+		if q < 0 || q >= d.Q {
+			addProblem("accepting state %d out of range", q)
+		}
+	}
+
+	for q, trans := range d.D {
+		letters := []rune{}
+		for letter := range trans {
+			letters = append(letters, letter)
+		}
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+
+		for _, letter := range letters {
+			if !d.S.Contains(letter) {
+				addProblem("state %d, rune %q: rune not in alphabet", q, letter)
+			}
+			if to := trans[letter]; to < 0 || to >= d.Q {
+				addProblem("state %d, rune %q: target state %d out of range",
+					q, letter, to)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{problems}
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package dfa_test
+
+import (
+	"encoding/json"
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/gods/sets"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		d        dfa.Dfa
+		problems []string
+	}{
+		{m1, nil},
+		{
+			dfa.Dfa{
+				Q:  1,
+				S:  sets.NewSetRune(),
+				D:  []map[rune]int{nil},
+				Qs: 0,
+				F:  sets.NewSetInt(),
+			},
+			nil,
+		},
+		{
+			dfa.Dfa{
+				Q: 2,
+				S: sets.NewSetRune('a'),
+				D: []map[rune]int{
+					{'a': 1, 'b': 2},
+					{'a': -1},
+				},
+				Qs: 2,
+				F:  sets.NewSetInt(1, 3),
+			},
+			[]string{
+				"start state 2 out of range",
+				"accepting state 3 out of range",
+				"state 0, rune 'b': rune not in alphabet",
+				"state 0, rune 'b': target state 2 out of range",
+				"state 1, rune 'a': target state -1 out of range",
+			},
+		},
+		{
+			dfa.Dfa{
+				Q:  2,
+				S:  sets.NewSetRune('a'),
+				D:  []map[rune]int{{'a': 0}},
+				Qs: 0,
+				F:  sets.NewSetInt(0),
+			},
+			[]string{"got 1 transition maps, want 2"},
+		},
+	}
+
+	for n, tc := range testCases {
+		err := tc.d.Validate()
+		if tc.problems == nil {
+			if err != nil {
+				t.Errorf("case %d, got %v, want nil", n+1, err)
+			}
+			continue
+		}
+
+		verr, ok := err.(*dfa.ValidationError)
+		if !ok {
+			t.Errorf("case %d, got %v, want *ValidationError", n+1, err)
+			continue
+		}
+		if !reflect.DeepEqual(verr.Problems, tc.problems) {
+			t.Errorf("case %d, got %q, want %q", n+1, verr.Problems, tc.problems)
+		}
+	}
+}
+
+func TestUnmarshalValidates(t *testing.T) {
+	var d dfa.Dfa
+	err := json.Unmarshal([]byte(`{"states":1,"alphabet":["a"],"start":0,`+
+		`"accepting":[],"transitions":[{"a":1}]}`), &d)
+	if _, ok := err.(*dfa.ValidationError); !ok {
+		t.Errorf("got %v, want *ValidationError", err)
+	}
+
+	err = d.UnmarshalText([]byte("dfa\n     'a'\n> 0  1\n"))
+	if _, ok := err.(*dfa.ValidationError); !ok {
+		t.Errorf("got %v, want *ValidationError", err)
+	}
+}
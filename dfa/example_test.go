@@ -3,6 +3,7 @@ package dfa_test
 import (
 	"fmt"
 	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/automata/regex"
 	"github.com/paulgriffiths/gods/sets"
 )
 
@@ -44,3 +45,11 @@ func Example() {
 	// DFA accepts prefix "aba" of string "ababb".
 	// DFA accepts prefix "a" of string "abbbb".
 }
+
+func ExampleDfa_ToRegex() {
+	d := regex.Compile("aa*|a").Dfa()
+	fmt.Println(d.Minimize().ToRegex())
+
+	// Output:
+	// aa* true
+}
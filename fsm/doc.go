@@ -0,0 +1,8 @@
+/*
+Package fsm implements deterministic finite state machines with
+output: Moore machines, whose outputs depend only on their states,
+and Mealy machines, whose outputs depend on their transitions. For
+nondeterministic machines relating input strings to output strings,
+which can be composed and determinized, see package fst.
+*/
+package fsm
@@ -0,0 +1,27 @@
+package fsm_test
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/automata/fsm"
+)
+
+func ExampleMealy_Run() {
+	type event string
+
+	door := fsm.Mealy[event, string]{
+		Q: 2,
+		S: []event{"push", "coin"},
+		D: []map[event]fsm.Transition[string]{
+			{"push": {0, "locked"}, "coin": {1, "unlock"}},
+			{"push": {0, "lock"}, "coin": {1, "refund"}},
+		},
+		Qs: 0,
+	}
+
+	fmt.Println(door.Run([]event{"push", "coin", "coin", "push"}))
+	fmt.Println(door.ToMoore("idle").Run([]event{"coin", "push"}))
+
+	// Output:
+	// [locked unlock refund lock]
+	// [idle unlock lock]
+}
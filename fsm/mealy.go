@@ -0,0 +1,144 @@
+package fsm
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"strconv"
+	"strings"
+)
+
+// Transition is a transition of a Mealy machine, giving the state it
+// enters and the output it produces.
+type Transition[O comparable] struct {
+	To     int // Next state
+	Output O   // Output
+}
+
+// Mealy is a Mealy machine, a deterministic finite automaton which
+// produces an output on each transition, over input symbols of type I
+// and outputs of type O. It has no accepting states. The alphabet
+// should not hold duplicates, and every symbol with a transition
+// should be in it.
+type Mealy[I, O comparable] struct {
+	Q  int                   // Number of states
+	S  []I                   // Input alphabet
+	D  []map[I]Transition[O] // Transition and output function
+	Qs int                   // Start state
+}
+
+// Run returns the outputs of the Mealy machine on the provided input,
+// one for each symbol. If the machine has no transition on some
+// symbol, Run stops and returns the outputs produced before it.
+func (m Mealy[I, O]) Run(input []I) []O {
+	currentState := m.Qs
+	result := []O{}
+
+	for _, symbol := range input {
+		t, ok := m.D[currentState][symbol]
+		if !ok {
+			break
+		}
+		currentState = t.To
+		result = append(result, t.Output)
+	}
+
+	return result
+}
+
+// ToMoore converts the Mealy machine to an equivalent Moore machine.
+// Each state of the Moore machine corresponds to a state of the Mealy
+// machine together with the output of a transition entering it, and
+// outputs that output. The start state outputs the provided initial
+// output, after which the Moore machine produces the same outputs as
+// the Mealy machine. Only states reachable from the start state are
+// included, numbered in breadth-first order from the start state,
+// which is state 0.
+func (m Mealy[I, O]) ToMoore(initial O) Moore[I, O] {
+	type pair struct {
+		q int
+		o O
+	}
+
+	result := Moore[I, O]{S: append([]I{}, m.S...)}
+	number := make(map[pair]int)
+	order := []pair{}
+	state := func(p pair) int {
+		n, ok := number[p]
+		if !ok {
+			n = len(order)
+			number[p] = n
+			order = append(order, p)
+			result.D = append(result.D, make(map[I]int))
+			result.L = append(result.L, p.o)
+		}
+		return n
+	}
+
+	result.Qs = state(pair{m.Qs, initial})
+	for i := 0; i < len(order); i++ {
+		for _, symbol := range m.S {
+			if t, ok := m.D[order[i].q][symbol]; ok {
+				result.D[i][symbol] = state(pair{t.To, t.Output})
+			}
+		}
+	}
+
+	result.Q = len(order)
+	return result
+}
+
+// Minimize returns the equivalent Mealy machine with the fewest
+// states, as described for Moore.Minimize, except that the states are
+// initially partitioned by the outputs of their transitions on each
+// symbol.
+func (m Mealy[I, O]) Minimize() Mealy[I, O] {
+	class := make([]int, m.Q)
+	rep := []int{}
+	outputs := make(map[O]int)
+	keys := make(map[string]int)
+	for q := range class {
+		var b strings.Builder
+		for _, symbol := range m.S {
+			b.WriteByte(',')
+			t, ok := m.D[q][symbol]
+			if !ok {
+				b.WriteString("-1")
+				continue
+			}
+			if _, ok := outputs[t.Output]; !ok {
+				outputs[t.Output] = len(outputs)
+			}
+			b.WriteString(strconv.Itoa(outputs[t.Output]))
+		}
+		c, ok := keys[b.String()]
+		if !ok {
+			c = len(rep)
+			keys[b.String()] = c
+			rep = append(rep, q)
+		}
+		class[q] = c
+	}
+
+	next := make([]map[I]int, len(m.D))
+	for q, trans := range m.D {
+		next[q] = make(map[I]int)
+		for symbol, t := range trans {
+			next[q][symbol] = t.To
+		}
+	}
+
+	d := minimize(m.S, next, m.Qs, class)
+	result := Mealy[I, O]{
+		Q:  d.Q,
+		S:  append([]I{}, m.S...),
+		D:  make([]map[I]Transition[O], d.Q),
+		Qs: d.Qs,
+	}
+	for q, to := range dfa.Relabel(d.Dfa, m.S).D {
+		result.D[q] = make(map[I]Transition[O])
+		original := rep[d.T[q].Elements()[0]]
+		for symbol, p := range to {
+			result.D[q][symbol] = Transition[O]{p, m.D[original][symbol].Output}
+		}
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package fsm_test
+
+import (
+	"github.com/paulgriffiths/automata/fsm"
+	"reflect"
+	"testing"
+)
+
+type request int
+
+const (
+	login request = iota
+	query
+	logout
+)
+
+type response string
+
+// handler responds to requests, refusing queries before a login.
+// States 2 and 3 both represent a logged in session.
+var handler = fsm.Mealy[request, response]{
+	Q: 4,
+	S: []request{login, query, logout},
+	D: []map[request]fsm.Transition[response]{
+		{login: {2, "welcome"}, query: {0, "denied"}, logout: {0, "denied"}},
+		{login: {1, "closed"}},
+		{login: {3, "already"}, query: {3, "result"}, logout: {1, "bye"}},
+		{login: {2, "already"}, query: {2, "result"}, logout: {1, "bye"}},
+	},
+	Qs: 0,
+}
+
+func TestMealyRun(t *testing.T) {
+	testCases := []struct {
+		input []request
+		want  []response
+	}{
+		{[]request{}, []response{}},
+		{[]request{query, login, query, logout}, []response{"denied", "welcome", "result", "bye"}},
+		{[]request{login, logout, query}, []response{"welcome", "bye"}},
+	}
+
+	for n, tc := range testCases {
+		if got := handler.Run(tc.input); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("case %d, got %v, want %v", n+1, got, tc.want)
+		}
+	}
+}
+
+func TestMealyMinimize(t *testing.T) {
+	m := handler.Minimize()
+	if m.Q != 3 {
+		t.Errorf("got %d states, want 3", m.Q)
+	}
+	for _, s := range allSequences(handler.S, 5) {
+		if got, want := m.Run(s), handler.Run(s); !reflect.DeepEqual(got, want) {
+			t.Errorf("input %v, got %v, want %v", s, got, want)
+		}
+	}
+}
+
+func TestMealyToMoore(t *testing.T) {
+	m := handler.ToMoore("ready")
+	if m.Q != 9 {
+		t.Errorf("got %d states, want 9", m.Q)
+	}
+	for _, s := range allSequences(handler.S, 5) {
+		want := append([]response{"ready"}, handler.Run(s)...)
+		if got := m.Run(s); !reflect.DeepEqual(got, want) {
+			t.Errorf("input %v, got %v, want %v", s, got, want)
+		}
+	}
+
+	back := m.ToMealy().Minimize()
+	for _, s := range allSequences(handler.S, 5) {
+		if got, want := back.Run(s), handler.Run(s); !reflect.DeepEqual(got, want) {
+			t.Errorf("input %v, got %v after round trip, want %v", s, got, want)
+		}
+	}
+}
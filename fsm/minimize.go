@@ -0,0 +1,45 @@
+package fsm
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/gods/sets"
+)
+
+// minimize minimizes a machine with the provided alphabet, transition
+// function and start state, starting from the provided initial
+// partition of its states into classes. The machine is converted to
+// a TaggedDfa in which every state is accepting and tagged with its
+// class, and each symbol is replaced by the rune giving its index in
+// the alphabet, and minimized. In the result, which is returned as
+// it is, each state is tagged with the class of the states it
+// represents.
+func minimize[I comparable](alphabet []I, trans []map[I]int, start int, class []int) dfa.TaggedDfa {
+	index := make(map[I]rune, len(alphabet))
+	for i, symbol := range alphabet {
+		index[symbol] = rune(i)
+	}
+
+	t := dfa.TaggedDfa{
+		Dfa: dfa.Dfa{
+			Q:  len(class),
+			S:  sets.NewSetRune(),
+			D:  make([]map[rune]int, len(class)),
+			Qs: start,
+			F:  sets.NewSetInt(),
+		},
+		T: make([]sets.SetInt, len(class)),
+	}
+	for i := range alphabet {
+		t.S.Insert(rune(i))
+	}
+	for q := range class {
+		t.D[q] = make(map[rune]int)
+		for symbol, to := range trans[q] {
+			t.D[q][index[symbol]] = to
+		}
+		t.F.Insert(q)
+		t.T[q] = sets.NewSetInt(class[q])
+	}
+
+	return t.Minimize()
+}
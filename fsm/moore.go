@@ -0,0 +1,111 @@
+package fsm
+
+import "github.com/paulgriffiths/automata/dfa"
+
+// Moore is a Moore machine, a deterministic finite automaton which
+// produces an output on entering each state, over input symbols of
+// type I and outputs of type O. It has no accepting states. The
+// alphabet should not hold duplicates, and every symbol with a
+// transition should be in it.
+type Moore[I, O comparable] struct {
+	Q  int         // Number of states
+	S  []I         // Input alphabet
+	D  []map[I]int // Transition function
+	L  []O         // Output of each state
+	Qs int         // Start state
+}
+
+// Run returns the outputs of the Moore machine on the provided input,
+// starting with the output of the start state, followed by the output
+// of the state entered on each symbol, so there is one more output
+// than there are symbols. If the machine has no transition on some
+// symbol, Run stops and returns the outputs produced before it.
+func (m Moore[I, O]) Run(input []I) []O {
+	currentState := m.Qs
+	result := []O{m.L[currentState]}
+
+	for _, symbol := range input {
+		next, ok := m.D[currentState][symbol]
+		if !ok {
+			break
+		}
+		currentState = next
+		result = append(result, m.L[currentState])
+	}
+
+	return result
+}
+
+// ToMealy converts the Moore machine to an equivalent Mealy machine
+// with the same states, whose transitions output the outputs of the
+// states they enter. The Mealy machine produces the same outputs as
+// the Moore machine, except for the output of the start state.
+func (m Moore[I, O]) ToMealy() Mealy[I, O] {
+	result := Mealy[I, O]{
+		Q:  m.Q,
+		S:  append([]I{}, m.S...),
+		D:  make([]map[I]Transition[O], len(m.D)),
+		Qs: m.Qs,
+	}
+	for q, trans := range m.D {
+		result.D[q] = make(map[I]Transition[O])
+		for symbol, to := range trans {
+			result.D[q][symbol] = Transition[O]{to, m.L[to]}
+		}
+	}
+	return result
+}
+
+// Minimize returns the equivalent Moore machine with the fewest
+// states. States which cannot be reached from the start state are
+// removed, and the remaining states are merged by partition
+// refinement, starting from a partition of the states by their
+// outputs, until no two states produce the same outputs on every
+// input. The states of the result are numbered in breadth-first
+// order from the start state, which is state 0.
+func (m Moore[I, O]) Minimize() Moore[I, O] {
+	class := make([]int, m.Q)
+	rep := []int{}
+	outputs := make(map[O]int)
+	for q := range class {
+		c, ok := outputs[m.L[q]]
+		if !ok {
+			c = len(rep)
+			outputs[m.L[q]] = c
+			rep = append(rep, q)
+		}
+		class[q] = c
+	}
+
+	d := minimize(m.S, m.D, m.Qs, class)
+	result := Moore[I, O]{
+		Q:  d.Q,
+		S:  append([]I{}, m.S...),
+		D:  dfa.Relabel(d.Dfa, m.S).D,
+		L:  make([]O, d.Q),
+		Qs: d.Qs,
+	}
+	for q := range result.L {
+		result.L[q] = m.L[rep[d.T[q].Elements()[0]]]
+	}
+	return result
+}
+
+// FromDfa returns a Moore machine with the same states and
+// transitions as the Dfa, labeling each state with the corresponding
+// output. If there are fewer labels than states, the remaining states
+// output the zero value of O. For example, labeling the accepting
+// states true and the others false gives a machine whose outputs
+// report whether each prefix of its input is accepted.
+func FromDfa[O comparable](d dfa.Dfa, labels []O) Moore[rune, O] {
+	m := dfa.FromDfa(d)
+	result := Moore[rune, O]{
+		Q:  m.Q,
+		S:  m.S,
+		D:  m.D,
+		L:  make([]O, m.Q),
+		Qs: m.Qs,
+	}
+	copy(result.L, labels)
+	return result
+}
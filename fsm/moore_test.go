@@ -0,0 +1,117 @@
+package fsm_test
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/automata/fsm"
+	"github.com/paulgriffiths/gods/sets"
+	"reflect"
+	"testing"
+)
+
+// allSequences returns all the sequences of the provided symbols with
+// length at most n.
+func allSequences[T any](symbols []T, n int) [][]T {
+	result := [][]T{{}}
+	last := [][]T{{}}
+	for i := 0; i < n; i++ {
+		next := [][]T{}
+		for _, s := range last {
+			for _, symbol := range symbols {
+				next = append(next, append(append([]T{}, s...), symbol))
+			}
+		}
+		result = append(result, next...)
+		last = next
+	}
+	return result
+}
+
+// mod3 outputs the remainder modulo 3 of each prefix of its input
+// read as a binary number. States 3 and 4 duplicate states 0 and 1,
+// and state 5 is unreachable.
+var mod3 = fsm.Moore[rune, int]{
+	Q: 6,
+	S: []rune{'0', '1'},
+	D: []map[rune]int{
+		{'0': 3, '1': 1},
+		{'0': 2, '1': 3},
+		{'0': 4, '1': 2},
+		{'0': 0, '1': 4},
+		{'0': 2, '1': 0},
+		{'0': 5, '1': 5},
+	},
+	L:  []int{0, 1, 2, 0, 1, 2},
+	Qs: 0,
+}
+
+func TestMooreRun(t *testing.T) {
+	testCases := []struct {
+		input string
+		want  []int
+	}{
+		{"", []int{0}},
+		{"110", []int{0, 1, 0, 0}},
+		{"1011", []int{0, 1, 2, 2, 2}},
+		{"10x1", []int{0, 1, 2}},
+	}
+
+	for n, tc := range testCases {
+		if got := mod3.Run([]rune(tc.input)); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("case %d, got %v, want %v", n+1, got, tc.want)
+		}
+	}
+}
+
+func TestMooreMinimize(t *testing.T) {
+	m := mod3.Minimize()
+	if m.Q != 3 {
+		t.Errorf("got %d states, want 3", m.Q)
+	}
+	for _, s := range allSequences(mod3.S, 6) {
+		if got, want := m.Run(s), mod3.Run(s); !reflect.DeepEqual(got, want) {
+			t.Errorf("input %q, got %v, want %v", string(s), got, want)
+		}
+	}
+}
+
+func TestMooreToMealy(t *testing.T) {
+	m := mod3.ToMealy()
+	for _, s := range allSequences(mod3.S, 5) {
+		if got, want := m.Run(s), mod3.Run(s)[1:]; !reflect.DeepEqual(got, want) {
+			t.Errorf("input %q, got %v, want %v", string(s), got, want)
+		}
+	}
+}
+
+func TestFromDfa(t *testing.T) {
+	d := dfa.Dfa{
+		Q: 3,
+		S: sets.NewSetRune('0', '1'),
+		D: []map[rune]int{
+			{'0': 0, '1': 1},
+			{'0': 2, '1': 1},
+			{'0': 1, '1': 1},
+		},
+		Qs: 0,
+		F:  sets.NewSetInt(1),
+	}
+	labels := []bool{}
+	for q := 0; q < d.Q; q++ {
+		labels = append(labels, d.F.Contains(q))
+	}
+
+	m := fsm.FromDfa(d, labels)
+	for _, s := range allSequences(m.S, 5) {
+		got := m.Run(s)
+		for i := range got {
+			if want := d.Accepts(string(s[:i])); got[i] != want {
+				t.Errorf("input %q, output %d, got %t, want %t",
+					string(s), i, got[i], want)
+			}
+		}
+	}
+
+	if got := fsm.FromDfa(d, []string{"start"}).L; !reflect.DeepEqual(got, []string{"start", "", ""}) {
+		t.Errorf("got labels %q", got)
+	}
+}
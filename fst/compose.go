@@ -0,0 +1,59 @@
+package fst
+
+import "github.com/paulgriffiths/gods/sets"
+
+// Compose returns the composition of two transducers, which
+// transduces a string to each output of b on each output of a on
+// that string. Its states are the pairs of a state of a and a state
+// of b reachable from the pair of their start states, numbered in
+// breadth-first order from the start state, which is state 0. A
+// transition of a writing a rune is paired with each transition of b
+// reading it, while transitions of a writing nothing and transitions
+// of b reading nothing are taken alone.
+func Compose(a, b Fst) Fst {
+	type pair struct{ p, q int }
+
+	result := Fst{F: sets.NewSetInt()}
+	number := make(map[pair]int)
+	order := []pair{}
+	state := func(s pair) int {
+		n, ok := number[s]
+		if !ok {
+			n = len(order)
+			number[s] = n
+			order = append(order, s)
+			result.D = append(result.D, nil)
+			if a.F.Contains(s.p) && b.F.Contains(s.q) {
+				result.F.Insert(n)
+			}
+		}
+		return n
+	}
+
+	result.Qs = state(pair{a.Qs, b.Qs})
+	for i := 0; i < len(order); i++ {
+		p, q := order[i].p, order[i].q
+		for _, ta := range a.D[p] {
+			if ta.Out == Epsilon {
+				to := state(pair{ta.To, q})
+				result.D[i] = append(result.D[i], Transition{ta.In, Epsilon, to})
+				continue
+			}
+			for _, tb := range b.D[q] {
+				if tb.In == ta.Out {
+					to := state(pair{ta.To, tb.To})
+					result.D[i] = append(result.D[i], Transition{ta.In, tb.Out, to})
+				}
+			}
+		}
+		for _, tb := range b.D[q] {
+			if tb.In == Epsilon {
+				to := state(pair{p, tb.To})
+				result.D[i] = append(result.D[i], Transition{Epsilon, tb.Out, to})
+			}
+		}
+	}
+
+	result.Q = len(order)
+	return result
+}
@@ -0,0 +1,292 @@
+package fst
+
+import (
+	"errors"
+	"fmt"
+	"github.com/paulgriffiths/gods/sets"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxDeterminizedStates is the largest number of states Determinize
+// creates before concluding that the transducer is not subsequential.
+const MaxDeterminizedStates = 10000
+
+// ErrNotSubsequential is returned by Determinize when the transducer
+// does not have an equivalent subsequential transducer.
+var ErrNotSubsequential = errors.New("fst: transducer is not subsequential")
+
+// Arc is a transition of a subsequential transducer, giving the
+// state it enters and the string it writes.
+type Arc struct {
+	To  int    // Next state
+	Out string // Output
+}
+
+// Subsequential implements a subsequential transducer, which is
+// deterministic on its input and writes a string on each transition,
+// and a final string on stopping in an accepting state.
+type Subsequential struct {
+	Q     int            // Number of states
+	D     []map[rune]Arc // Transition function
+	Qs    int            // Start state
+	F     sets.SetInt    // Set of accepting states
+	Final []string       // Final output of each state
+}
+
+// Transduce returns the single output of the subsequential transducer
+// on the provided input and true, or the empty string and false if it
+// does not accept the input.
+func (s Subsequential) Transduce(input string) (string, bool) {
+	var b strings.Builder
+	currentState := s.Qs
+
+	for _, letter := range input {
+		arc, ok := s.D[currentState][letter]
+		if !ok {
+			return "", false
+		}
+		b.WriteString(arc.Out)
+		currentState = arc.To
+	}
+
+	if !s.F.Contains(currentState) {
+		return "", false
+	}
+	b.WriteString(s.Final[currentState])
+	return b.String(), true
+}
+
+// ToFst converts the subsequential transducer to an equivalent Fst.
+// Each transition writing more than one rune becomes a chain of
+// transitions, the first reading its input rune and the rest reading
+// nothing, and each non-empty final output is written on a chain of
+// transitions from the accepting state to a new accepting state.
+func (s Subsequential) ToFst() Fst {
+	t := Fst{
+		Q:  s.Q,
+		D:  make([][]Transition, s.Q),
+		Qs: s.Qs,
+		F:  sets.NewSetInt(),
+	}
+
+	chain := func(from int, in rune, out string, to int) {
+		runes := []rune(out)
+		if len(runes) == 0 {
+			t.D[from] = append(t.D[from], Transition{in, Epsilon, to})
+			return
+		}
+		for i, r := range runes {
+			next := to
+			if i < len(runes)-1 {
+				next = t.Q
+				t.D = append(t.D, nil)
+				t.Q++
+			}
+			t.D[from] = append(t.D[from], Transition{in, r, next})
+			from, in = next, Epsilon
+		}
+	}
+
+	for q, arcs := range s.D {
+		letters := []rune{}
+		for letter := range arcs {
+			letters = append(letters, letter)
+		}
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+		for _, letter := range letters {
+			chain(q, letter, arcs[letter].Out, arcs[letter].To)
+		}
+	}
+
+	for _, q := range s.F.Elements() {
+		if s.Final[q] == "" {
+			t.F.Insert(q)
+			continue
+		}
+		final := t.Q
+		t.D = append(t.D, nil)
+		t.Q++
+		t.F.Insert(final)
+		chain(q, Epsilon, s.Final[q], final)
+	}
+
+	return t
+}
+
+// residual is a state of a transducer together with output which has
+// been read past but not yet written.
+type residual struct {
+	q   int
+	out string
+}
+
+// Determinize converts the transducer to an equivalent subsequential
+// transducer, using Mohri's algorithm. Each state of the result is a
+// set of states of the transducer, each paired with a residual string
+// which it has yet to write, and each transition writes the longest
+// common prefix of the outputs of the transitions it combines. The
+// transducer must be functional, writing at most one output for each
+// input, and must be trimmed of states from which no accepting state
+// can be reached. If two paths on the same input reach the same
+// state with different outputs, or reach accepting states with
+// different outputs, the transducer is not functional and an error
+// is returned. A functional transducer which is not subsequential
+// causes residual strings to grow without bound, and
+// ErrNotSubsequential is returned once MaxDeterminizedStates states
+// have been created.
+func (t Fst) Determinize() (Subsequential, error) {
+	result := Subsequential{F: sets.NewSetInt()}
+	number := make(map[string]int)
+	subsets := [][]residual{}
+
+	state := func(subset []residual) int {
+		key := subsetKey(subset)
+		n, ok := number[key]
+		if !ok {
+			n = len(subsets)
+			number[key] = n
+			subsets = append(subsets, subset)
+			result.D = append(result.D, make(map[rune]Arc))
+			result.Final = append(result.Final, "")
+		}
+		return n
+	}
+
+	start, err := t.residualClosure([]residual{{t.Qs, ""}})
+	if err != nil {
+		return Subsequential{}, err
+	}
+	result.Qs = state(start)
+
+	for i := 0; i < len(subsets); i++ {
+		if len(subsets) > MaxDeterminizedStates {
+			return Subsequential{}, ErrNotSubsequential
+		}
+
+		final := []string{}
+		for _, r := range subsets[i] {
+			if t.F.Contains(r.q) {
+				final = append(final, r.out)
+			}
+		}
+		if len(final) > 0 {
+			for _, out := range final[1:] {
+				if out != final[0] {
+					return Subsequential{}, fmt.Errorf(
+						"fst: transducer is not functional: outputs %q and %q for the same input",
+						final[0], out)
+				}
+			}
+			result.F.Insert(i)
+			result.Final[i] = final[0]
+		}
+
+		moves := make(map[rune][]residual)
+		for _, r := range subsets[i] {
+			for _, tr := range t.D[r.q] {
+				if tr.In != Epsilon {
+					moves[tr.In] = append(moves[tr.In],
+						residual{tr.To, r.out + label(tr.Out)})
+				}
+			}
+		}
+
+		letters := []rune{}
+		for letter := range moves {
+			letters = append(letters, letter)
+		}
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+
+		for _, letter := range letters {
+			next, err := t.residualClosure(moves[letter])
+			if err != nil {
+				return Subsequential{}, err
+			}
+			prefix := commonPrefix(next)
+			for j := range next {
+				next[j].out = next[j].out[len(prefix):]
+			}
+			result.D[i][letter] = Arc{state(next), prefix}
+		}
+	}
+
+	result.Q = len(subsets)
+	return result, nil
+}
+
+// residualClosure returns the provided residuals together with those
+// reachable from them on transitions with Epsilon inputs, sorted by
+// state. It is an error for a state to be reached with two different
+// residuals.
+func (t Fst) residualClosure(rs []residual) ([]residual, error) {
+	out := make(map[int]string)
+	stack := []residual{}
+	add := func(r residual) error {
+		if prev, ok := out[r.q]; ok {
+			if prev != r.out {
+				return fmt.Errorf(
+					"fst: transducer is not functional: state %d reached with outputs %q and %q",
+					r.q, prev, r.out)
+			}
+			return nil
+		}
+		out[r.q] = r.out
+		stack = append(stack, r)
+		return nil
+	}
+
+	for _, r := range rs {
+		if err := add(r); err != nil {
+			return nil, err
+		}
+	}
+	for len(stack) > 0 {
+		r := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, tr := range t.D[r.q] {
+			if tr.In == Epsilon {
+				if err := add(residual{tr.To, r.out + label(tr.Out)}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	result := []residual{}
+	for q, s := range out {
+		result = append(result, residual{q, s})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].q < result[j].q })
+	return result, nil
+}
+
+// subsetKey returns a string identifying a sorted set of residuals.
+func subsetKey(rs []residual) string {
+	var b strings.Builder
+	for _, r := range rs {
+		fmt.Fprintf(&b, "%d:%q;", r.q, r.out)
+	}
+	return b.String()
+}
+
+// commonPrefix returns the longest common prefix of the outputs of
+// the provided residuals.
+func commonPrefix(rs []residual) string {
+	if len(rs) == 0 {
+		return ""
+	}
+	prefix := rs[0].out
+	for _, r := range rs[1:] {
+		n := 0
+		for n < len(prefix) && n < len(r.out) && prefix[n] == r.out[n] {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	for !utf8.ValidString(prefix) {
+		prefix = prefix[:len(prefix)-1]
+	}
+	return prefix
+}
@@ -0,0 +1,89 @@
+package fst_test
+
+import (
+	"errors"
+	"github.com/paulgriffiths/automata/fst"
+	"github.com/paulgriffiths/gods/sets"
+	"testing"
+)
+
+func TestDeterminize(t *testing.T) {
+	// delayed writes "x" for ab and "y" for ac, choosing between them
+	// on reading a, so its output is delayed by the determinization.
+	delayed := fst.Fst{
+		Q: 5,
+		D: [][]fst.Transition{
+			{{In: 'a', Out: 'x', To: 1}, {In: 'a', Out: 'y', To: 2}},
+			{{In: 'b', Out: fst.Epsilon, To: 3}},
+			{{In: 'c', Out: fst.Epsilon, To: 3}},
+			{{In: fst.Epsilon, Out: '!', To: 4}},
+			{},
+		},
+		Qs: 0,
+		F:  sets.NewSetInt(4),
+	}
+
+	testCases := []fst.Fst{
+		fst.Compose(lower, fst.FromMap(map[rune]string{'a': "aa", 'b': "ñ"})),
+		delayed,
+		fst.FromMap(map[rune]string{'ß': "ss", 'a': "", 'b': "b"}),
+	}
+
+	for n, tc := range testCases {
+		s, err := tc.Determinize()
+		if err != nil {
+			t.Errorf("case %d, couldn't determinize: %v", n+1, err)
+			continue
+		}
+		back := s.ToFst()
+		for _, input := range allStrings("aAbBcß", 4) {
+			want := tc.Transduce(input)
+			got, ok := s.Transduce(input)
+			if ok != (want != nil) || ok && (len(want) != 1 || got != want[0]) {
+				t.Errorf("case %d, string %q, got %q, %t, want %q",
+					n+1, input, got, ok, want)
+			}
+			if again := back.Transduce(input); len(again) != len(want) ||
+				len(want) == 1 && again[0] != want[0] {
+				t.Errorf("case %d, string %q, got %q from Fst, want %q",
+					n+1, input, again, want)
+			}
+		}
+	}
+
+	s, _ := delayed.Determinize()
+	if arc := s.D[s.Qs]['a']; arc.Out != "" {
+		t.Errorf("got output %q on a, want none", arc.Out)
+	}
+}
+
+func TestDeterminizeErrors(t *testing.T) {
+	ambiguous := fst.Fst{
+		Q: 2,
+		D: [][]fst.Transition{
+			{{In: 'a', Out: 'x', To: 1}, {In: 'a', Out: 'y', To: 1}},
+			{},
+		},
+		Qs: 0,
+		F:  sets.NewSetInt(1),
+	}
+	if _, err := ambiguous.Determinize(); err == nil {
+		t.Errorf("got no error for non-functional transducer")
+	}
+
+	// last writes each a as x if the input ends with b, and as y if
+	// it ends with c, which a subsequential transducer cannot do.
+	last := fst.Fst{
+		Q: 3,
+		D: [][]fst.Transition{
+			{{In: 'a', Out: 'x', To: 1}, {In: 'a', Out: 'y', To: 2}},
+			{{In: 'a', Out: 'x', To: 1}, {In: 'b', Out: 'b', To: 0}},
+			{{In: 'a', Out: 'y', To: 2}, {In: 'c', Out: 'c', To: 0}},
+		},
+		Qs: 0,
+		F:  sets.NewSetInt(0),
+	}
+	if _, err := last.Determinize(); !errors.Is(err, fst.ErrNotSubsequential) {
+		t.Errorf("got error %v, want %v", err, fst.ErrNotSubsequential)
+	}
+}
@@ -0,0 +1,9 @@
+/*
+Package fst implements finite-state transducers, which are finite
+automata whose transitions read an input rune and write an output
+rune, either of which may be omitted. A transducer defines a relation
+between input and output strings, such as a rewriting rule, and
+transducers may be composed, inverted and, if they are subsequential,
+determinized.
+*/
+package fst
@@ -0,0 +1,29 @@
+package fst_test
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/automata/fst"
+)
+
+func ExampleCompose() {
+	fold := fst.FromMap(map[rune]string{
+		'S': "s", 's': "s", 'T': "t", 't': "t", 'R': "r", 'r': "r",
+		'A': "a", 'a': "a", 'E': "e", 'e': "e", 'ß': "ß",
+	})
+	translit := fst.FromMap(map[rune]string{
+		's': "s", 't': "t", 'r': "r", 'a': "a", 'e': "e", 'ß': "ss",
+	})
+
+	normalize := fst.Compose(fold, translit)
+	fmt.Println(normalize.Transduce("STRAßE"))
+
+	s, err := normalize.Determinize()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(s.Transduce("Straße"))
+
+	// Output:
+	// [strasse]
+	// strasse true
+}
@@ -0,0 +1,230 @@
+package fst
+
+import (
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/gods/sets"
+	"sort"
+)
+
+// Epsilon is the label of the input or output side of a transition
+// which reads or writes nothing.
+const Epsilon rune = -1
+
+// Transition is a transition of a finite-state transducer.
+type Transition struct {
+	In  rune // Input rune, or Epsilon
+	Out rune // Output rune, or Epsilon
+	To  int  // Next state
+}
+
+// Fst implements a nondeterministic finite-state transducer.
+type Fst struct {
+	Q  int            // Number of states
+	D  [][]Transition // Transitions from each state
+	Qs int            // Start state
+	F  sets.SetInt    // Set of accepting states
+}
+
+// config is a state of the transducer together with the output
+// written on the way to it.
+type config struct {
+	q   int
+	out string
+}
+
+// Transduce returns, in ascending order and without duplicates, the
+// outputs of the transducer on the provided input, or nil if it does
+// not accept the input. A path following more consecutive transitions
+// with Epsilon inputs than the transducer has states must go around a
+// cycle, and is not followed, so the output of such a cycle appears
+// a bounded number of times.
+func (t Fst) Transduce(input string) []string {
+	current := t.closure([]config{{t.Qs, ""}})
+
+	for _, letter := range input {
+		next := []config{}
+		seen := make(map[config]bool)
+		for _, c := range current {
+			for _, tr := range t.D[c.q] {
+				if tr.In != letter {
+					continue
+				}
+				n := config{tr.To, c.out + label(tr.Out)}
+				if !seen[n] {
+					seen[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		current = t.closure(next)
+	}
+
+	var result []string
+	seen := make(map[string]bool)
+	for _, c := range current {
+		if t.F.Contains(c.q) && !seen[c.out] {
+			seen[c.out] = true
+			result = append(result, c.out)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// closure returns the provided configurations together with those
+// reachable from them on transitions with Epsilon inputs, following
+// at most Q such transitions in a row.
+func (t Fst) closure(configs []config) []config {
+	seen := make(map[config]bool)
+	for _, c := range configs {
+		seen[c] = true
+	}
+
+	result := append([]config{}, configs...)
+	frontier := configs
+	for i := 0; i < t.Q && len(frontier) > 0; i++ {
+		next := []config{}
+		for _, c := range frontier {
+			for _, tr := range t.D[c.q] {
+				if tr.In != Epsilon {
+					continue
+				}
+				n := config{tr.To, c.out + label(tr.Out)}
+				if !seen[n] {
+					seen[n] = true
+					next = append(next, n)
+				}
+			}
+		}
+		result = append(result, next...)
+		frontier = next
+	}
+
+	return result
+}
+
+// label returns a transition label as a string, which is empty for
+// Epsilon.
+func label(r rune) string {
+	if r == Epsilon {
+		return ""
+	}
+	return string(r)
+}
+
+// Invert returns the inverse of the transducer, which has the input
+// and output of each transition swapped, and so transduces each
+// output of the transducer to the inputs which produce it.
+func (t Fst) Invert() Fst {
+	result := Fst{
+		Q:  t.Q,
+		D:  make([][]Transition, len(t.D)),
+		Qs: t.Qs,
+		F:  sets.NewSetInt(t.F.Elements()...),
+	}
+	for q, trans := range t.D {
+		for _, tr := range trans {
+			result.D[q] = append(result.D[q], Transition{tr.Out, tr.In, tr.To})
+		}
+	}
+	return result
+}
+
+// InputNfa returns the projection of the transducer on its input, an
+// Nfa with the same states which accepts exactly the inputs the
+// transducer accepts. Transitions with Epsilon inputs become
+// e-transitions.
+func (t Fst) InputNfa() nfa.Nfa {
+	return t.project(func(tr Transition) rune { return tr.In })
+}
+
+// OutputNfa returns the projection of the transducer on its output,
+// an Nfa with the same states which accepts exactly the strings the
+// transducer outputs. Transitions with Epsilon outputs become
+// e-transitions.
+func (t Fst) OutputNfa() nfa.Nfa {
+	return t.project(func(tr Transition) rune { return tr.Out })
+}
+
+// project returns an Nfa with the same states as the transducer, and
+// a transition on the rune selected from each of its transitions.
+func (t Fst) project(side func(Transition) rune) nfa.Nfa {
+	n := nfa.Nfa{
+		Q:  t.Q,
+		S:  sets.NewSetRune(),
+		D:  make([]map[rune]sets.SetInt, t.Q),
+		Qs: t.Qs,
+		F:  sets.NewSetInt(t.F.Elements()...),
+	}
+
+	for q := range n.D {
+		n.D[q] = make(map[rune]sets.SetInt)
+	}
+	for q, trans := range t.D {
+		for _, tr := range trans {
+			letter := side(tr)
+			if letter != Epsilon {
+				if _, ok := n.D[q][letter]; !ok {
+					n.D[q][letter] = sets.NewSetInt()
+				}
+				n.D[q][letter].Insert(tr.To)
+				n.S.Insert(letter)
+				continue
+			}
+			if n.E == nil {
+				n.E = make([]sets.SetInt, t.Q)
+				for i := range n.E {
+					n.E[i] = sets.NewSetInt()
+				}
+			}
+			n.E[q].Insert(tr.To)
+		}
+	}
+
+	return n
+}
+
+// FromMap returns a transducer which accepts the strings made of the
+// runes in the domain of the provided mapping, and replaces each of
+// their runes with the string it maps to. A rune mapped to itself is
+// left unchanged, and a rune mapped to the empty string is deleted.
+func FromMap(mapping map[rune]string) Fst {
+	t := Fst{
+		Q:  1,
+		D:  make([][]Transition, 1),
+		Qs: 0,
+		F:  sets.NewSetInt(0),
+	}
+
+	letters := []rune{}
+	for letter := range mapping {
+		letters = append(letters, letter)
+	}
+	sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+
+	for _, letter := range letters {
+		out := []rune(mapping[letter])
+		if len(out) <= 1 {
+			tr := Transition{letter, Epsilon, 0}
+			if len(out) == 1 {
+				tr.Out = out[0]
+			}
+			t.D[0] = append(t.D[0], tr)
+			continue
+		}
+
+		from, in := 0, letter
+		for i, r := range out {
+			to := 0
+			if i < len(out)-1 {
+				to = t.Q
+				t.D = append(t.D, nil)
+				t.Q++
+			}
+			t.D[from] = append(t.D[from], Transition{in, r, to})
+			from, in = to, Epsilon
+		}
+	}
+
+	return t
+}
@@ -0,0 +1,144 @@
+package fst_test
+
+import (
+	"github.com/paulgriffiths/automata/fst"
+	"github.com/paulgriffiths/gods/sets"
+	"reflect"
+	"testing"
+)
+
+// allStrings returns all strings over the provided alphabet with
+// lengths up to and including n.
+func allStrings(alphabet string, n int) []string {
+	result := []string{""}
+	last := []string{""}
+	for i := 0; i < n; i++ {
+		next := []string{}
+		for _, s := range last {
+			for _, r := range alphabet {
+				next = append(next, s+string(r))
+			}
+		}
+		result = append(result, next...)
+		last = next
+	}
+	return result
+}
+
+// lower folds the letters A, B and a, b to lower case.
+var lower = fst.FromMap(map[rune]string{'A': "a", 'B': "b", 'a': "a", 'b': "b"})
+
+// optional optionally deletes each b, and may insert an x at the
+// end, so it has several outputs for most inputs.
+var optional = fst.Fst{
+	Q: 2,
+	D: [][]fst.Transition{
+		{
+			{In: 'a', Out: 'a', To: 0},
+			{In: 'b', Out: 'b', To: 0},
+			{In: 'b', Out: fst.Epsilon, To: 0},
+			{In: fst.Epsilon, Out: 'x', To: 1},
+		},
+		{},
+	},
+	Qs: 0,
+	F:  sets.NewSetInt(0, 1),
+}
+
+func TestTransduce(t *testing.T) {
+	testCases := []struct {
+		t     fst.Fst
+		input string
+		want  []string
+	}{
+		{lower, "AbBa", []string{"abba"}},
+		{lower, "", []string{""}},
+		{lower, "AcB", nil},
+		{optional, "ab", []string{"a", "ab", "abx", "ax"}},
+		{optional, "", []string{"", "x"}},
+		{optional, "c", nil},
+		{fst.FromMap(map[rune]string{'ß': "ss", 'a': "", 'b': "b"}), "aßb", []string{"ssb"}},
+	}
+
+	for n, tc := range testCases {
+		if got := tc.t.Transduce(tc.input); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("case %d, got %q, want %q", n+1, got, tc.want)
+		}
+	}
+}
+
+func TestTransduceEpsilonCycle(t *testing.T) {
+	loop := fst.Fst{
+		Q:  1,
+		D:  [][]fst.Transition{{{In: fst.Epsilon, Out: 'x', To: 0}}},
+		Qs: 0,
+		F:  sets.NewSetInt(0),
+	}
+	if got, want := loop.Transduce(""), []string{"", "x"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestInvert(t *testing.T) {
+	inv := lower.Invert()
+	want := []string{"AB", "Ab", "aB", "ab"}
+	if got := inv.Transduce("ab"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got := inv.Invert().Transduce("Ab"); !reflect.DeepEqual(got, []string{"ab"}) {
+		t.Errorf("got %q, want %q", got, []string{"ab"})
+	}
+}
+
+func TestProjections(t *testing.T) {
+	in, out := optional.InputNfa(), optional.OutputNfa()
+	for _, s := range allStrings("abx", 4) {
+		if got, want := in.Accepts(s), optional.Transduce(s) != nil; got != want {
+			t.Errorf("string %q, got input %t, want %t", s, got, want)
+		}
+		if got, want := out.Accepts(s), optional.Invert().Transduce(s) != nil; got != want {
+			t.Errorf("string %q, got output %t, want %t", s, got, want)
+		}
+	}
+	if err := in.Validate(); err != nil {
+		t.Errorf("invalid input automaton: %v", err)
+	}
+	if in.E == nil || lower.InputNfa().E != nil {
+		t.Errorf("got wrong e-transitions")
+	}
+}
+
+func TestCompose(t *testing.T) {
+	double := fst.FromMap(map[rune]string{'a': "aa", 'b': "b"})
+	testCases := []struct {
+		a, b fst.Fst
+	}{
+		{lower, double},
+		{lower, optional},
+		{optional, double},
+		{double, optional},
+		{optional, optional.Invert()},
+	}
+
+	for n, tc := range testCases {
+		c := fst.Compose(tc.a, tc.b)
+		for _, s := range allStrings("aAbB", 3) {
+			outputs := make(map[string]bool)
+			for _, mid := range tc.a.Transduce(s) {
+				for _, out := range tc.b.Transduce(mid) {
+					outputs[out] = true
+				}
+			}
+			got := c.Transduce(s)
+			if len(got) != len(outputs) {
+				t.Errorf("case %d, string %q, got %q, want %v", n+1, s, got, outputs)
+				continue
+			}
+			for _, out := range got {
+				if !outputs[out] {
+					t.Errorf("case %d, string %q, unexpected output %q", n+1, s, out)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Package dot writes descriptions of finite automata in the DOT graph
+description language used by Graphviz.
+*/
+package dot
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Graph accumulates the description of a directed graph.
+type Graph struct {
+	b strings.Builder
+}
+
+// New creates a Graph with the specified name, laid out from left to
+// right with circular nodes.
+func New(name string) *Graph {
+	g := &Graph{}
+	fmt.Fprintf(&g.b, "digraph %s {\n", strconv.Quote(name))
+	g.b.WriteString("\trankdir=LR;\n")
+	g.b.WriteString("\tnode [shape=circle];\n")
+	return g
+}
+
+// State adds a state, labeled with its number and, if provided, the
+// additional annotation. Accepting states are drawn with a double
+// circle.
+func (g *Graph) State(q int, annotation string, accepting bool) {
+	label := strconv.Itoa(q)
+	if annotation != "" {
+		label += "\n" + annotation
+	}
+	shape := "circle"
+	if accepting {
+		shape = "doublecircle"
+	}
+	fmt.Fprintf(&g.b, "\t%d [label=%s, shape=%s];\n",
+		q, strconv.Quote(label), shape)
+}
+
+// Start adds an arrow pointing to the start state.
+func (g *Graph) Start(q int) {
+	g.b.WriteString("\tstart [shape=point];\n")
+	fmt.Fprintf(&g.b, "\tstart -> %d;\n", q)
+}
+
+// Edge adds a transition with the specified label.
+func (g *Graph) Edge(from, to int, label string) {
+	fmt.Fprintf(&g.b, "\t%d -> %d [label=%s];\n",
+		from, to, strconv.Quote(label))
+}
+
+// WriteTo writes the completed graph description to w.
+func (g *Graph) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, g.b.String()+"}\n")
+	return int64(n), err
+}
+
+// Label returns an edge label for a set of runes, merging runs of
+// three or more consecutive runes into ranges such as a-z.
+func Label(runes []rune) string {
+	sorted := append([]rune{}, runes...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	parts := []string{}
+	for i := 0; i < len(sorted); {
+		j := i
+		for j+1 < len(sorted) && sorted[j+1] == sorted[j]+1 {
+			j++
+		}
+		if j-i >= 2 {
+			parts = append(parts, Rune(sorted[i])+"-"+Rune(sorted[j]))
+		} else {
+			for k := i; k <= j; k++ {
+				parts = append(parts, Rune(sorted[k]))
+			}
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// Rune returns a rune as it should appear in a label. Printable
+// runes appear as themselves, except for those used as separators
+// in labels, which are quoted along with all other runes.
+func Rune(r rune) string {
+	if unicode.IsPrint(r) && !strings.ContainsRune("-,' ", r) {
+		return string(r)
+	}
+	return strconv.QuoteRune(r)
+}
+
+// Set returns a label for a set of states, such as {0,1,4}.
+func Set(states []int) string {
+	sorted := append([]int{}, states...)
+	sort.Ints(sorted)
+	parts := make([]string, len(sorted))
+	for i, q := range sorted {
+		parts[i] = strconv.Itoa(q)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
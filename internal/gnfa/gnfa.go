@@ -0,0 +1,177 @@
+/*
+Package gnfa implements generalized nondeterministic finite automata,
+whose transitions are labeled with regular expressions, and uses them
+to convert finite automata to regular expressions by state
+elimination.
+*/
+package gnfa
+
+import (
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"unicode"
+)
+
+// Gnfa implements a generalized nondeterministic finite automaton.
+// In addition to the states of the automaton being converted, it has
+// a new start state with an e-transition to the original start state,
+// and a new accepting state to which every original accepting state
+// has an e-transition.
+type Gnfa struct {
+	r             [][]*syntax.Node
+	start, accept int
+}
+
+// New creates a Gnfa for an automaton with q states, the specified
+// start state, and the specified accepting states. Transitions may
+// then be added with AddRune and AddEpsilon.
+func New(q, start int, accepting []int) *Gnfa {
+	g := &Gnfa{
+		r:      make([][]*syntax.Node, q+2),
+		start:  q,
+		accept: q + 1,
+	}
+	for i := range g.r {
+		g.r[i] = make([]*syntax.Node, q+2)
+	}
+
+	g.AddEpsilon(g.start, start)
+	for _, state := range accepting {
+		g.AddEpsilon(state, g.accept)
+	}
+	return g
+}
+
+// AddRune adds a transition on rune r.
+func (g *Gnfa) AddRune(from, to int, r rune) {
+	g.add(from, to, &syntax.Node{Op: syntax.OpLiteral, Rune: r})
+}
+
+// AddEpsilon adds an e-transition.
+func (g *Gnfa) AddEpsilon(from, to int) {
+	g.add(from, to, &syntax.Node{Op: syntax.OpEmptyMatch})
+}
+
+// add adds a transition labeled with a regular expression, forming
+// the union with any existing label.
+func (g *Gnfa) add(from, to int, label *syntax.Node) {
+	if g.r[from][to] == nil {
+		g.r[from][to] = label
+		return
+	}
+	g.r[from][to] = alternate(g.r[from][to], label)
+}
+
+// Regex eliminates all the original states and returns the syntax
+// tree of the regular expression labeling the sole remaining
+// transition from the new start state to the new accepting state.
+// States are eliminated in the order which adds the fewest new
+// transitions, which tends to produce shorter regular expressions.
+// If the automaton accepts no strings, an OpNoMatch node is
+// returned.
+func (g *Gnfa) Regex() *syntax.Node {
+	remaining := make(map[int]bool)
+	for q := 0; q < g.start; q++ {
+		remaining[q] = true
+	}
+
+	for len(remaining) > 0 {
+		best, bestCost := -1, 0
+		for q := 0; q < g.start; q++ {
+			if !remaining[q] {
+				continue
+			}
+			if cost := g.cost(q); best == -1 || cost < bestCost {
+				best, bestCost = q, cost
+			}
+		}
+		g.eliminate(best)
+		delete(remaining, best)
+	}
+
+	if g.r[g.start][g.accept] == nil {
+		return &syntax.Node{Op: syntax.OpNoMatch}
+	}
+	return g.r[g.start][g.accept].Simplify()
+}
+
+// cost returns the number of transitions which would be created by
+// eliminating state q.
+func (g *Gnfa) cost(q int) int {
+	in, out := 0, 0
+	for i := range g.r {
+		if i == q {
+			continue
+		}
+		if g.r[i][q] != nil {
+			in++
+		}
+		if g.r[q][i] != nil {
+			out++
+		}
+	}
+	return in * out
+}
+
+// eliminate removes state q, replacing every path i -> q -> j with
+// a direct transition from i to j labeled R(i,q) R(q,q)* R(q,j).
+func (g *Gnfa) eliminate(q int) {
+	var loop *syntax.Node
+	if g.r[q][q] != nil {
+		loop = &syntax.Node{Op: syntax.OpStar, Sub: []*syntax.Node{g.r[q][q]}}
+	}
+
+	for i := range g.r {
+		if i == q || g.r[i][q] == nil {
+			continue
+		}
+		for j := range g.r {
+			if j == q || g.r[q][j] == nil {
+				continue
+			}
+			path := []*syntax.Node{g.r[i][q]}
+			if loop != nil {
+				path = append(path, loop)
+			}
+			path = append(path, g.r[q][j])
+			g.add(i, j, (&syntax.Node{Op: syntax.OpConcat, Sub: path}).Simplify())
+		}
+	}
+
+	for i := range g.r {
+		g.r[i][q] = nil
+		g.r[q][i] = nil
+	}
+}
+
+// alternate returns the simplified union of two labels.
+func alternate(a, b *syntax.Node) *syntax.Node {
+	node := &syntax.Node{Op: syntax.OpAlternate, Sub: []*syntax.Node{a, b}}
+	return node.Simplify()
+}
+
+// String returns a regular expression in the syntax accepted by
+// package regex. That syntax has no way to write the empty language
+// or the empty string, and its only symbols are letters and digits,
+// so String returns false if the simplified syntax tree n holds a
+// node of kind OpNoMatch or OpEmptyMatch, or any other literal.
+func String(n *syntax.Node) (string, bool) {
+	ok := true
+	var check func(*syntax.Node)
+	check = func(m *syntax.Node) {
+		switch m.Op {
+		case syntax.OpNoMatch, syntax.OpEmptyMatch:
+			ok = false
+		case syntax.OpLiteral:
+			ok = ok && (unicode.IsLetter(m.Rune) || unicode.IsDigit(m.Rune))
+		}
+		for _, sub := range m.Sub {
+			check(sub)
+		}
+	}
+	check(n)
+
+	if !ok {
+		return "", false
+	}
+	return n.String(), true
+}
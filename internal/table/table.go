@@ -0,0 +1,245 @@
+/*
+Package table reads and writes the human-readable state table format
+used to serialize finite automata as text.
+
+A table begins with a line naming the kind of automaton, followed by
+a header line listing the input symbols, each written as a quoted Go
+rune literal, or as ε for e-transitions. The header line must
+immediately follow the kind line, and is blank if there are no input
+symbols. Each subsequent line describes one state: an optional
+marker, > for the start state and * for an accepting state, the
+state number, and then one cell for each input symbol. Other blank
+lines and lines beginning with # are ignored.
+*/
+package table
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+)
+
+// Epsilon is the header token for the column of e-transitions.
+const Epsilon = "ε"
+
+// Row is a row of a state table.
+type Row struct {
+	Start, Accepting bool     // State markers
+	State            int      // State number
+	Cells            []string // One cell for each header symbol
+}
+
+// Table is a parsed state table.
+type Table struct {
+	Kind   string   // Kind of automaton
+	Header []string // Input symbols, as tokens
+	Rows   []Row
+}
+
+// Write formats a table with aligned columns.
+func Write(t Table) []byte {
+	var b bytes.Buffer
+	b.WriteString(t.Kind + "\n")
+
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "\t\t%s\t\n", strings.Join(t.Header, "\t"))
+	for _, row := range t.Rows {
+		marker := ""
+		if row.Start {
+			marker += ">"
+		}
+		if row.Accepting {
+			marker += "*"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t\n",
+			marker, row.State, strings.Join(row.Cells, "\t"))
+	}
+	w.Flush()
+
+	// Remove the trailing padding left by the tabwriter.
+	lines := strings.Split(b.String(), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " ")
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// Read parses a table of the specified kind.
+func Read(text []byte, kind string) (Table, error) {
+	t := Table{}
+	scanner := bufio.NewScanner(bytes.NewReader(text))
+	line := 0
+	for scanner.Scan() {
+		line++
+		trimmed := strings.TrimSpace(scanner.Text())
+		if t.Kind != "" && t.Header == nil {
+			tokens, err := Tokenize(trimmed)
+			if err != nil {
+				return t, fmt.Errorf("line %d: %v", line, err)
+			}
+			t.Header = tokens
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if t.Kind == "" {
+			if trimmed != kind {
+				return t, fmt.Errorf("line %d: got %q, want %q",
+					line, trimmed, kind)
+			}
+			t.Kind = trimmed
+			continue
+		}
+
+		tokens, err := Tokenize(trimmed)
+		if err != nil {
+			return t, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		row, err := parseRow(tokens)
+		if err != nil {
+			return t, fmt.Errorf("line %d: %v", line, err)
+		}
+		if len(row.Cells) != len(t.Header) {
+			return t, fmt.Errorf("line %d: got %d cells, want %d",
+				line, len(row.Cells), len(t.Header))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	if t.Kind == "" {
+		return t, fmt.Errorf("missing %q", kind)
+	}
+	if t.Header == nil {
+		return t, errors.New("missing header")
+	}
+	return t, scanner.Err()
+}
+
+// parseRow parses the tokens of a row.
+func parseRow(tokens []string) (Row, error) {
+	row := Row{}
+	if len(tokens) > 0 && strings.Trim(tokens[0], ">*") == "" {
+		row.Start = strings.Contains(tokens[0], ">")
+		row.Accepting = strings.Contains(tokens[0], "*")
+		tokens = tokens[1:]
+	}
+	if len(tokens) == 0 {
+		return row, errors.New("missing state")
+	}
+
+	state, err := strconv.Atoi(tokens[0])
+	if err != nil {
+		return row, fmt.Errorf("bad state %q", tokens[0])
+	}
+	row.State = state
+	row.Cells = tokens[1:]
+	return row, nil
+}
+
+// Tokenize splits a line into whitespace-separated tokens, treating
+// a quoted rune literal, which may itself contain whitespace, as a
+// single token.
+func Tokenize(line string) ([]string, error) {
+	tokens := []string{}
+	for {
+		line = strings.TrimLeftFunc(line, unicode.IsSpace)
+		if line == "" {
+			return tokens, nil
+		}
+		if line[0] == '\'' {
+			token, err := strconv.QuotedPrefix(line)
+			if err != nil {
+				return nil, fmt.Errorf("bad rune literal in %q", line)
+			}
+			tokens = append(tokens, token)
+			line = line[len(token):]
+			continue
+		}
+		end := strings.IndexFunc(line, unicode.IsSpace)
+		if end == -1 {
+			end = len(line)
+		}
+		tokens = append(tokens, line[:end])
+		line = line[end:]
+	}
+}
+
+// FormatRune returns the header token for a rune.
+func FormatRune(r rune) string {
+	return strconv.QuoteRune(r)
+}
+
+// ParseRune parses the header token for a rune.
+func ParseRune(token string) (rune, error) {
+	s, err := strconv.Unquote(token)
+	if err != nil || !strings.HasPrefix(token, "'") {
+		return 0, fmt.Errorf("bad rune literal %q", token)
+	}
+	return []rune(s)[0], nil
+}
+
+// FormatSet returns the cell for a set of states, such as {0,2}, or
+// - for the empty set.
+func FormatSet(states []int) string {
+	if len(states) == 0 {
+		return "-"
+	}
+	parts := make([]string, len(states))
+	for i, q := range states {
+		parts[i] = strconv.Itoa(q)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+// ParseSet parses the cell for a set of states.
+func ParseSet(cell string) ([]int, error) {
+	if cell == "-" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(cell, "{") || !strings.HasSuffix(cell, "}") {
+		return nil, fmt.Errorf("bad state set %q", cell)
+	}
+	states := []int{}
+	inner := strings.TrimSuffix(strings.TrimPrefix(cell, "{"), "}")
+	if inner == "" {
+		return states, nil
+	}
+	for _, part := range strings.Split(inner, ",") {
+		q, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("bad state set %q", cell)
+		}
+		states = append(states, q)
+	}
+	return states, nil
+}
+
+// FormatState returns the cell for a single state, or - if there
+// is no transition.
+func FormatState(q int, ok bool) string {
+	if !ok {
+		return "-"
+	}
+	return strconv.Itoa(q)
+}
+
+// ParseState parses the cell for a single state, returning false if
+// there is no transition.
+func ParseState(cell string) (int, bool, error) {
+	if cell == "-" {
+		return 0, false, nil
+	}
+	q, err := strconv.Atoi(cell)
+	if err != nil {
+		return 0, false, fmt.Errorf("bad state %q", cell)
+	}
+	return q, true, nil
+}
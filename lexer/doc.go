@@ -0,0 +1,13 @@
+/*
+Package lexer implements a lexical analyzer generator.
+
+A lexer is defined by a list of rules, each pairing a regular
+expression in the syntax of package regex with a token type. The
+rules are compiled into a single deterministic finite automaton whose
+accepting states are tagged with the rules they match. The input is
+then split into tokens by maximal munch: at each position, the rule
+matching the longest prefix of the remaining input is chosen, and
+where several rules match that prefix, the rule listed first wins.
+Input which no rule matches is returned as error tokens.
+*/
+package lexer
@@ -0,0 +1,35 @@
+package lexer_test
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/automata/lexer"
+)
+
+func ExampleLexer_Next() {
+	const (
+		keyword lexer.Type = iota
+		word
+		number
+	)
+
+	def, err := lexer.CompileWithOptions([]lexer.Rule{
+		{"let", keyword},
+		{"(a|b|e|l|s|t|x)(a|b|e|l|s|t|x)*", word},
+		{"(0|1|2|3|4|5|6|7|8|9)(0|1|2|3|4|5|6|7|8|9)*", number},
+	}, lexer.Options{SkipSpace: true})
+	if err != nil {
+		panic(err)
+	}
+
+	l := def.Lexer("let x\n  lets 42?")
+	for tok := l.Next(); tok.Type != lexer.EOF; tok = l.Next() {
+		fmt.Printf("%s %d %q\n", tok.Pos, tok.Type, tok.Value)
+	}
+
+	// Output:
+	// 1:1 0 "let"
+	// 1:5 1 "x"
+	// 2:3 1 "lets"
+	// 2:8 2 "42"
+	// 2:10 -1 "?"
+}
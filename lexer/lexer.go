@@ -0,0 +1,199 @@
+package lexer
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/automata/regex"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Type identifies the type of a token. Types are chosen by the
+// rules defining a lexer, except for the reserved types Error and
+// EOF, which are negative.
+type Type int
+
+// Reserved token types.
+const (
+	Error Type = -1 // Input matched by no rule
+	EOF   Type = -2 // End of input
+)
+
+// Rule pairs a regular expression with the type of the tokens it
+// matches.
+type Rule struct {
+	Pattern string // Regular expression
+	Type    Type   // Type of matching tokens
+}
+
+// Options controls the compilation of a lexer definition.
+type Options struct {
+	SkipSpace bool // Skip white space between tokens
+}
+
+// Position is a location in the input.
+type Position struct {
+	Offset int // Byte offset, starting at 0
+	Line   int // Line number, starting at 1
+	Column int // Column number in runes, starting at 1
+}
+
+// String returns the position in the form line:column.
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
+}
+
+// Token is a token found in the input.
+type Token struct {
+	Type  Type     // Type of token
+	Value string   // Text of token
+	Pos   Position // Position of the start of the token
+}
+
+// Definition is a compiled set of lexer rules.
+type Definition struct {
+	rules []Rule
+	opts  Options
+	d     dfa.TaggedDfa
+}
+
+// Compile compiles a list of rules into a lexer definition. Rules
+// earlier in the list take priority over later rules when both match
+// the same longest prefix of the input.
+func Compile(rules []Rule) (*Definition, error) {
+	return CompileWithOptions(rules, Options{})
+}
+
+// CompileWithOptions compiles a list of rules into a lexer definition
+// using the specified options. It is an error for any rule to have a
+// negative type, or to match the empty string, since the lexer could
+// make no progress on such a match.
+func CompileWithOptions(rules []Rule, opts Options) (*Definition, error) {
+	patterns := []string{}
+	for i, rule := range rules {
+		if rule.Type < 0 {
+			return nil, fmt.Errorf("lexer: rule %d: reserved token type %d",
+				i, rule.Type)
+		}
+		patterns = append(patterns, rule.Pattern)
+	}
+
+	set, err := regex.CompileSet(patterns)
+	if err != nil {
+		return nil, fmt.Errorf("lexer: %v", err)
+	}
+	if matches := set.Matches(""); matches != nil {
+		return nil, fmt.Errorf("lexer: rule %d matches the empty string",
+			matches[0])
+	}
+
+	return &Definition{
+		rules: append([]Rule{}, rules...),
+		opts:  opts,
+		d:     set.Dfa(),
+	}, nil
+}
+
+// Lexer splits an input string into tokens.
+type Lexer struct {
+	def   *Definition
+	input string
+	pos   Position
+}
+
+// Lexer returns a Lexer for the provided input.
+func (d *Definition) Lexer(input string) *Lexer {
+	return &Lexer{
+		def:   d,
+		input: input,
+		pos:   Position{Offset: 0, Line: 1, Column: 1},
+	}
+}
+
+// Next returns the next token in the input. The longest prefix of
+// the remaining input matched by any rule is returned as a token of
+// the type of the first such rule. If no rule matches, the input up
+// to the next position at which a rule matches, or the end of the
+// input, is returned as a token of type Error. Once the input is
+// exhausted, Next returns tokens of type EOF.
+func (l *Lexer) Next() Token {
+	if l.def.opts.SkipSpace {
+		for l.pos.Offset < len(l.input) {
+			r, size := utf8.DecodeRuneInString(l.input[l.pos.Offset:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			l.advance(size)
+		}
+	}
+
+	start := l.pos
+	if start.Offset == len(l.input) {
+		return Token{Type: EOF, Pos: start}
+	}
+
+	if n, rule := l.def.longest(l.input[start.Offset:]); n > 0 {
+		l.advance(n)
+		return Token{l.def.rules[rule].Type, l.input[start.Offset:l.pos.Offset], start}
+	}
+
+	for l.pos.Offset < len(l.input) {
+		r, size := utf8.DecodeRuneInString(l.input[l.pos.Offset:])
+		if l.pos.Offset > start.Offset {
+			if l.def.opts.SkipSpace && unicode.IsSpace(r) {
+				break
+			}
+			if n, _ := l.def.longest(l.input[l.pos.Offset:]); n > 0 {
+				break
+			}
+		}
+		l.advance(size)
+	}
+	return Token{Error, l.input[start.Offset:l.pos.Offset], start}
+}
+
+// Tokens returns all the remaining tokens in the input, not including
+// the final EOF token.
+func (l *Lexer) Tokens() []Token {
+	tokens := []Token{}
+	for tok := l.Next(); tok.Type != EOF; tok = l.Next() {
+		tokens = append(tokens, tok)
+	}
+	return tokens
+}
+
+// advance moves the position forward by n bytes.
+func (l *Lexer) advance(n int) {
+	for _, r := range l.input[l.pos.Offset : l.pos.Offset+n] {
+		if r == '\n' {
+			l.pos.Line++
+			l.pos.Column = 1
+		} else {
+			l.pos.Column++
+		}
+	}
+	l.pos.Offset += n
+}
+
+// longest returns the length in bytes of the longest prefix of s
+// matched by any rule, and the index of the first rule matching it.
+// If no rule matches a non-empty prefix, it returns zero.
+func (d *Definition) longest(s string) (int, int) {
+	state := d.d.Qs
+	length, rule := 0, 0
+
+	for n := 0; n < len(s); {
+		letter, size := utf8.DecodeRuneInString(s[n:])
+		n += size
+		next, ok := d.d.D[state][letter]
+		if !ok {
+			break
+		}
+		state = next
+		if tags := d.d.T[state]; !tags.IsEmpty() {
+			length, rule = n, tags.Elements()[0]
+		}
+	}
+
+	return length, rule
+}
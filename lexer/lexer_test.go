@@ -0,0 +1,148 @@
+package lexer_test
+
+import (
+	"github.com/paulgriffiths/automata/lexer"
+	"testing"
+)
+
+const (
+	tIdent lexer.Type = iota
+	tNumber
+	tIf
+	tIfdef
+)
+
+var rules = []lexer.Rule{
+	{"if", tIf},
+	{"ifdef", tIfdef},
+	{"(a|b|c|d|e|f|i|x|y|z)(a|b|c|d|e|f|i|x|y|z|0|1|2)*", tIdent},
+	{"(0|1|2)(0|1|2)*", tNumber},
+}
+
+func TestLexer(t *testing.T) {
+	def, err := lexer.CompileWithOptions(rules, lexer.Options{SkipSpace: true})
+	if err != nil {
+		t.Fatalf("couldn't compile rules: %v", err)
+	}
+
+	testCases := []struct {
+		input  string
+		tokens []lexer.Token
+	}{
+		{"", []lexer.Token{}},
+		{"   \n ", []lexer.Token{}},
+		{
+			"if ifdef ifd iff x1 012",
+			[]lexer.Token{
+				{tIf, "if", lexer.Position{0, 1, 1}},
+				{tIfdef, "ifdef", lexer.Position{3, 1, 4}},
+				{tIdent, "ifd", lexer.Position{9, 1, 10}},
+				{tIdent, "iff", lexer.Position{13, 1, 14}},
+				{tIdent, "x1", lexer.Position{17, 1, 18}},
+				{tNumber, "012", lexer.Position{20, 1, 21}},
+			},
+		},
+		{
+			"12ab\n  if",
+			[]lexer.Token{
+				{tNumber, "12", lexer.Position{0, 1, 1}},
+				{tIdent, "ab", lexer.Position{2, 1, 3}},
+				{tIf, "if", lexer.Position{7, 2, 3}},
+			},
+		},
+		{
+			"a+-b 9q 1",
+			[]lexer.Token{
+				{tIdent, "a", lexer.Position{0, 1, 1}},
+				{lexer.Error, "+-", lexer.Position{1, 1, 2}},
+				{tIdent, "b", lexer.Position{3, 1, 4}},
+				{lexer.Error, "9q", lexer.Position{5, 1, 6}},
+				{tNumber, "1", lexer.Position{8, 1, 9}},
+			},
+		},
+		{
+			"ä€a",
+			[]lexer.Token{
+				{lexer.Error, "ä€", lexer.Position{0, 1, 1}},
+				{tIdent, "a", lexer.Position{5, 1, 3}},
+			},
+		},
+	}
+
+	for n, tc := range testCases {
+		got := def.Lexer(tc.input).Tokens()
+		if len(got) != len(tc.tokens) {
+			t.Errorf("case %d, got %v, want %v", n+1, got, tc.tokens)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.tokens[i] {
+				t.Errorf("case %d, token %d, got %v, want %v",
+					n+1, i, got[i], tc.tokens[i])
+			}
+		}
+	}
+}
+
+func TestLexerWithoutSkip(t *testing.T) {
+	def, err := lexer.Compile(rules)
+	if err != nil {
+		t.Fatalf("couldn't compile rules: %v", err)
+	}
+
+	l := def.Lexer("ab 12")
+	want := []lexer.Token{
+		{tIdent, "ab", lexer.Position{0, 1, 1}},
+		{lexer.Error, " ", lexer.Position{2, 1, 3}},
+		{tNumber, "12", lexer.Position{3, 1, 4}},
+		{lexer.EOF, "", lexer.Position{5, 1, 6}},
+		{lexer.EOF, "", lexer.Position{5, 1, 6}},
+	}
+	for i, w := range want {
+		if got := l.Next(); got != w {
+			t.Errorf("token %d, got %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestRulePriority(t *testing.T) {
+	testCases := []struct {
+		rules []lexer.Rule
+		want  lexer.Type
+	}{
+		{[]lexer.Rule{{"if", 1}, {"(i|f)(i|f)*", 2}}, 1},
+		{[]lexer.Rule{{"(i|f)(i|f)*", 2}, {"if", 1}}, 2},
+		{[]lexer.Rule{{"i", 1}, {"(i|f)(i|f)*", 2}}, 2},
+	}
+
+	for n, tc := range testCases {
+		def, err := lexer.Compile(tc.rules)
+		if err != nil {
+			t.Fatalf("case %d, couldn't compile rules: %v", n+1, err)
+		}
+		if got := def.Lexer("if").Next(); got.Type != tc.want || got.Value != "if" {
+			t.Errorf("case %d, got %v, want type %d", n+1, got, tc.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	testCases := [][]lexer.Rule{
+		{{"a", 0}, {"b*", 1}},
+		{{"a", 0}, {"(b", 1}},
+		{{"a", lexer.Error}},
+		{{"a|", 0}},
+	}
+
+	for n, tc := range testCases {
+		if _, err := lexer.Compile(tc); err == nil {
+			t.Errorf("case %d, got no error", n+1)
+		}
+	}
+}
+
+func TestPositionString(t *testing.T) {
+	if got := (lexer.Position{Offset: 10, Line: 3, Column: 7}).String(); got != "3:7" {
+		t.Errorf("got %q, want %q", got, "3:7")
+	}
+}
@@ -2,34 +2,34 @@ package nfa
 
 import "github.com/paulgriffiths/gods/sets"
 
-type dstate struct {
+type dstate[T comparable] struct {
 	nfaState sets.SetInt
-	trans    map[rune]int
+	trans    map[T]int
 }
 
-func newDstate(s sets.SetInt) dstate {
-	return dstate{s, make(map[rune]int)}
+func newDstate[T comparable](s sets.SetInt) dstate[T] {
+	return dstate[T]{s, make(map[T]int)}
 }
 
-type dtran []dstate
+type dtran[T comparable] []dstate[T]
 
-func newDtran(s sets.SetInt) dtran {
-	return dtran{newDstate(s)}
+func newDtran[T comparable](s sets.SetInt) dtran[T] {
+	return dtran[T]{newDstate[T](s)}
 }
 
-func (d dtran) length() int {
+func (d dtran[T]) length() int {
 	return len(d)
 }
 
-func (d *dtran) appendState(s sets.SetInt) {
-	*d = append(*d, newDstate(s))
+func (d *dtran[T]) appendState(s sets.SetInt) {
+	*d = append(*d, newDstate[T](s))
 }
 
-func (d dtran) addTrans(from, to int, a rune) {
+func (d dtran[T]) addTrans(from, to int, a T) {
 	d[from].trans[a] = to
 }
 
-func (d dtran) stateExists(s sets.SetInt) (int, bool) {
+func (d dtran[T]) stateExists(s sets.SetInt) (int, bool) {
 	for i, state := range d {
 		if state.nfaState.Equals(s) {
 			return i, true
@@ -11,12 +11,19 @@ func Example() {
 		Q: 5,
 		S: sets.NewSetRune('a', 'b'),
 		D: []map[rune]sets.SetInt{
-			{0: sets.NewSetInt(1, 3)},
+			{},
 			{'a': sets.NewSetInt(2)},
 			{'a': sets.NewSetInt(2)},
 			{'b': sets.NewSetInt(4)},
 			{'b': sets.NewSetInt(4)},
 		},
+		E: []sets.SetInt{
+			sets.NewSetInt(1, 3),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+		},
 		Qs: 0,
 		F:  sets.NewSetInt(2, 4),
 	}
@@ -1,66 +1,76 @@
 package nfa
 
-import "github.com/paulgriffiths/gods/sets"
+import (
+	"github.com/paulgriffiths/gods/sets"
+	"sort"
+)
 
 // Nfa implements a nondeterministic finite automaton.
 type Nfa struct {
 	Q  int                    // Number of states
 	S  sets.SetRune           // Alphabet
 	D  []map[rune]sets.SetInt // Transition function
+	E  []sets.SetInt          // e-transitions, may be nil if none
 	Qs int                    // Start state
 	F  sets.SetInt            // Set of accepting states
 }
 
 // Accepts returns true if the NFA accepts the provided string.
 func (n Nfa) Accepts(input string) bool {
-	current := n.EclosureS(n.Qs)
+	m := n.machine(nil)
+	current := m.EclosureS(m.Qs)
 	for _, letter := range input {
-		current = n.EclosureT(n.Move(current, letter))
+		if current = m.step(current, letter); current.IsEmpty() {
+			return false
+		}
 	}
-	return !n.F.Intersection(current).IsEmpty()
+	return !m.F.Intersection(current).IsEmpty()
 }
 
 // EclosureS returns the set of states reachable from the specified
 // state on e-transitions alone. Note that a path can have zero edges,
 // so any state is reachable from itself by an e-labeled path.
 func (n Nfa) EclosureS(s int) sets.SetInt {
-	current := sets.NewSetInt(s)
-	ecl := current
-	prevLength := -1
-
-	for ecl.Length() != prevLength {
-		prevLength = ecl.Length()
-		next := sets.NewSetInt()
-		for _, state := range current.Elements() {
-			if eStates, ok := n.D[state][0]; ok {
-				ecl.Merge(eStates)
-				next.Merge(eStates)
-			}
-		}
-		current = next
-	}
-
-	return ecl
+	return n.machine(nil).EclosureS(s)
 }
 
 // EclosureT returns the set of states reachable from the provided
 // set of states on e-transitions alone.
 func (n Nfa) EclosureT(t sets.SetInt) sets.SetInt {
-	ecl := sets.NewSetInt()
-	for _, state := range t.Elements() {
-		ecl.Merge(n.EclosureS(state))
-	}
-	return ecl
+	return n.machine(nil).EclosureT(t)
 }
 
 // Move returns the set of states reachable from set t on
 // input symbol a.
 func (n Nfa) Move(t sets.SetInt, a rune) sets.SetInt {
-	trans := sets.NewSetInt()
-	for _, state := range t.Elements() {
-		if p, ok := n.D[state][a]; ok {
-			trans.Merge(p)
+	return n.machine(nil).Move(t, a)
+}
+
+// machine returns the Nfa as a Machine over runes with the provided
+// alphabet, sharing its transitions and accepting states.
+func (n Nfa) machine(alphabet []rune) Machine[rune] {
+	return Machine[rune]{
+		Q:  n.Q,
+		S:  alphabet,
+		D:  n.D,
+		E:  n.E,
+		Qs: n.Qs,
+		F:  n.F,
+	}
+}
+
+// alphabet returns, in ascending order, the letters of the alphabet
+// together with any other runes on which the Nfa has transitions.
+func (n Nfa) alphabet() []rune {
+	letters := sets.NewSetRune(n.S.Elements()...)
+	for _, trans := range n.D {
+		for letter := range trans {
+			letters.Insert(letter)
 		}
 	}
-	return trans
+	alphabet := letters.Elements()
+	sort.Slice(alphabet, func(i, j int) bool {
+		return alphabet[i] < alphabet[j]
+	})
+	return alphabet
 }
@@ -24,6 +24,7 @@ func TestNfa1(t *testing.T) {
 			{'b': sets.NewSetInt(3)},
 			{},
 		},
+		nil,
 		0,
 		sets.NewSetInt(3),
 	}
@@ -80,12 +81,19 @@ func TestNfa2(t *testing.T) {
 		5,
 		sets.NewSetRune('a', 'b'),
 		[]map[rune]sets.SetInt{
-			{0: sets.NewSetInt(1, 3)},
+			{},
 			{'a': sets.NewSetInt(2)},
 			{'a': sets.NewSetInt(2)},
 			{'b': sets.NewSetInt(4)},
 			{'b': sets.NewSetInt(4)},
 		},
+		[]sets.SetInt{
+			sets.NewSetInt(1, 3),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+		},
 		0,
 		sets.NewSetInt(2, 4),
 	}
@@ -132,13 +140,15 @@ func TestNfa3(t *testing.T) {
 		[]map[rune]sets.SetInt{
 			{'a': sets.NewSetInt(0, 1), 'b': sets.NewSetInt(0)},
 			{'a': sets.NewSetInt(1, 2), 'b': sets.NewSetInt(1)},
-			{
-				0:   sets.NewSetInt(0),
-				'a': sets.NewSetInt(2),
-				'b': sets.NewSetInt(2, 3),
-			},
+			{'a': sets.NewSetInt(2), 'b': sets.NewSetInt(2, 3)},
 			{},
 		},
+		[]sets.SetInt{
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+			sets.NewSetInt(0),
+			sets.NewSetInt(),
+		},
 		0,
 		sets.NewSetInt(3),
 	}
@@ -185,10 +195,16 @@ func TestNfa4(t *testing.T) {
 		4,
 		sets.NewSetRune('a', 'b'),
 		[]map[rune]sets.SetInt{
-			{0: sets.NewSetInt(3), 'a': sets.NewSetInt(1)},
-			{0: sets.NewSetInt(0), 'b': sets.NewSetInt(2)},
-			{0: sets.NewSetInt(1), 'b': sets.NewSetInt(3)},
-			{0: sets.NewSetInt(2), 'a': sets.NewSetInt(0)},
+			{'a': sets.NewSetInt(1)},
+			{'b': sets.NewSetInt(2)},
+			{'b': sets.NewSetInt(3)},
+			{'a': sets.NewSetInt(0)},
+		},
+		[]sets.SetInt{
+			sets.NewSetInt(3),
+			sets.NewSetInt(0),
+			sets.NewSetInt(1),
+			sets.NewSetInt(2),
 		},
 		0,
 		sets.NewSetInt(3),
@@ -236,10 +252,16 @@ func TestNfa5(t *testing.T) {
 		sets.NewSetRune('0', '1'),
 		[]map[rune]sets.SetInt{
 			{'0': sets.NewSetInt(0), '1': sets.NewSetInt(0, 1)},
-			{0: sets.NewSetInt(2), '0': sets.NewSetInt(2)},
+			{'0': sets.NewSetInt(2)},
 			{'1': sets.NewSetInt(3)},
 			{'0': sets.NewSetInt(3), '1': sets.NewSetInt(3)},
 		},
+		[]sets.SetInt{
+			sets.NewSetInt(),
+			sets.NewSetInt(2),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+		},
 		0,
 		sets.NewSetInt(3),
 	}
@@ -288,6 +310,7 @@ func TestNfa6(t *testing.T) {
 			{'0': sets.NewSetInt(3), '1': sets.NewSetInt(3)},
 			{},
 		},
+		nil,
 		0,
 		sets.NewSetInt(3),
 	}
@@ -330,13 +353,21 @@ func TestNfa7(t *testing.T) {
 		6,
 		sets.NewSetRune('0', '1'),
 		[]map[rune]sets.SetInt{
-			{0: sets.NewSetInt(1, 3)},
+			{},
 			{'0': sets.NewSetInt(2)},
 			{'0': sets.NewSetInt(1)},
 			{'0': sets.NewSetInt(4)},
 			{'0': sets.NewSetInt(5)},
 			{'0': sets.NewSetInt(3)},
 		},
+		[]sets.SetInt{
+			sets.NewSetInt(1, 3),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+		},
 		0,
 		sets.NewSetInt(1, 3),
 	}
@@ -371,10 +402,15 @@ func TestNfa8(t *testing.T) {
 		3,
 		sets.NewSetRune('a', 'b'),
 		[]map[rune]sets.SetInt{
-			{0: sets.NewSetInt(2), 'b': sets.NewSetInt(1)},
+			{'b': sets.NewSetInt(1)},
 			{'a': sets.NewSetInt(1, 2), 'b': sets.NewSetInt(2)},
 			{'a': sets.NewSetInt(0)},
 		},
+		[]sets.SetInt{
+			sets.NewSetInt(2),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+		},
 		0,
 		sets.NewSetInt(0),
 	}
@@ -0,0 +1,119 @@
+package nfa
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/gods/sets"
+)
+
+// Builder constructs an Nfa one state and transition at a time,
+// keeping the number of states, the alphabet, the transition function
+// and the e-transitions consistent. The zero value is not usable; use
+// NewBuilder.
+type Builder struct {
+	n        Nfa
+	problems []string
+}
+
+// NewBuilder returns a Builder for an Nfa with no states. Unless
+// SetStart is called, the start state is state 0.
+func NewBuilder() *Builder {
+	return &Builder{
+		n: Nfa{
+			S: sets.NewSetRune(),
+			F: sets.NewSetInt(),
+		},
+	}
+}
+
+// AddState adds a new, non-accepting state with no transitions, and
+// returns its number.
+func (b *Builder) AddState() int {
+	b.n.D = append(b.n.D, make(map[rune]sets.SetInt))
+	b.n.E = append(b.n.E, sets.NewSetInt())
+	b.n.Q++
+	return b.n.Q - 1
+}
+
+// AddTransition adds a transition from state from to state to on the
+// provided rune, and adds the rune to the alphabet. The target state
+// need not have been added yet, but must have been by the time Build
+// is called.
+func (b *Builder) AddTransition(from int, r rune, to int) {
+	if from < 0 || from >= b.n.Q {
+		b.addProblem("state %d, rune %q: source state out of range", from, r)
+		return
+	}
+	if _, ok := b.n.D[from][r]; !ok {
+		b.n.D[from][r] = sets.NewSetInt()
+	}
+	b.n.S.Insert(r)
+	b.n.D[from][r].Insert(to)
+}
+
+// AddEpsilon adds an e-transition from state from to state to. The
+// target state need not have been added yet, but must have been by
+// the time Build is called.
+func (b *Builder) AddEpsilon(from, to int) {
+	if from < 0 || from >= b.n.Q {
+		b.addProblem("state %d, ε: source state out of range", from)
+		return
+	}
+	b.n.E[from].Insert(to)
+}
+
+// SetStart sets the start state.
+func (b *Builder) SetStart(q int) {
+	b.n.Qs = q
+}
+
+// SetAccepting makes the provided state an accepting state.
+func (b *Builder) SetAccepting(q int) {
+	b.n.F.Insert(q)
+}
+
+// Build returns the constructed Nfa, which has nil e-transitions if
+// AddEpsilon was never called. If any errors occurred while building
+// it, or it is not well-formed, the error is a *ValidationError
+// describing every problem found. The Builder may continue to be
+// used, and does not share any state with the returned Nfa.
+func (b *Builder) Build() (Nfa, error) {
+	result := Nfa{
+		Q:  b.n.Q,
+		S:  sets.NewSetRune(b.n.S.Elements()...),
+		D:  make([]map[rune]sets.SetInt, b.n.Q),
+		Qs: b.n.Qs,
+		F:  sets.NewSetInt(b.n.F.Elements()...),
+	}
+	for q, trans := range b.n.D {
+		result.D[q] = make(map[rune]sets.SetInt)
+		for letter, states := range trans {
+			result.D[q][letter] = sets.NewSetInt(states.Elements()...)
+		}
+	}
+	for q, states := range b.n.E {
+		if states.IsEmpty() {
+			continue
+		}
+		if result.E == nil {
+			result.E = make([]sets.SetInt, b.n.Q)
+			for i := range result.E {
+				result.E[i] = sets.NewSetInt()
+			}
+		}
+		result.E[q].Insert(states.Elements()...)
+	}
+
+	problems := append([]string{}, b.problems...)
+	if err := result.Validate(); err != nil {
+		problems = append(problems, err.(*ValidationError).Problems...)
+	}
+	if len(problems) > 0 {
+		return Nfa{}, &ValidationError{problems}
+	}
+	return result, nil
+}
+
+// addProblem records an error to be reported by Build.
+func (b *Builder) addProblem(format string, args ...interface{}) {
+	b.problems = append(b.problems, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,96 @@
+package nfa_test
+
+import (
+	"github.com/paulgriffiths/automata/nfa"
+	"reflect"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	b := nfa.NewBuilder()
+	for i := 0; i < 4; i++ {
+		b.AddState()
+	}
+	b.AddTransition(0, 'a', 0)
+	b.AddTransition(0, 'a', 1)
+	b.AddTransition(0, 'b', 0)
+	b.AddTransition(1, 'a', 1)
+	b.AddTransition(1, 'a', 2)
+	b.AddTransition(1, 'b', 1)
+	b.AddTransition(2, 'a', 2)
+	b.AddTransition(2, 'b', 2)
+	b.AddTransition(2, 'b', 3)
+	b.AddEpsilon(2, 0)
+	b.SetStart(0)
+	b.SetAccepting(3)
+
+	n, err := b.Build()
+	if err != nil {
+		t.Fatalf("couldn't build Nfa: %v", err)
+	}
+	if !equalNfas(n, nfa3) {
+		t.Errorf("got %v, want %v", n, nfa3)
+	}
+
+	// The built Nfa must not share state with the Builder.
+	b.AddTransition(3, 'c', 0)
+	if n.S.Contains('c') || len(n.D[3]) != 0 {
+		t.Errorf("Nfa changed after building")
+	}
+}
+
+func TestBuilderNoEpsilons(t *testing.T) {
+	b := nfa.NewBuilder()
+	q := b.AddState()
+	b.AddTransition(q, 'a', q)
+	b.SetAccepting(q)
+
+	n, err := b.Build()
+	if err != nil {
+		t.Fatalf("couldn't build Nfa: %v", err)
+	}
+	if n.E != nil {
+		t.Errorf("got e-transitions %v, want nil", n.E)
+	}
+	if !n.Accepts("aaa") || n.Accepts("b") {
+		t.Errorf("Nfa doesn't recognize a*")
+	}
+}
+
+func TestBuilderErrors(t *testing.T) {
+	b := nfa.NewBuilder()
+	b.AddState()
+	b.AddTransition(0, 'a', 1)
+	b.AddTransition(2, 'b', 0)
+	b.AddEpsilon(0, 3)
+	b.AddEpsilon(-1, 0)
+	b.SetStart(1)
+
+	want := []string{
+		"state 2, rune 'b': source state out of range",
+		"state -1, ε: source state out of range",
+		"start state 1 out of range",
+		"state 0, rune 'a': target state 1 out of range",
+		"state 0, ε: target state 3 out of range",
+	}
+
+	_, err := b.Build()
+	verr, ok := err.(*nfa.ValidationError)
+	if !ok {
+		t.Fatalf("got %v, want *ValidationError", err)
+	}
+	if !reflect.DeepEqual(verr.Problems, want) {
+		t.Errorf("got %q, want %q", verr.Problems, want)
+	}
+
+	// Adding the missing states fixes the remaining problems, except
+	// for those reported when adding transitions.
+	b.AddState()
+	b.AddState()
+	b.AddState()
+	_, err = b.Build()
+	if verr, ok := err.(*nfa.ValidationError); !ok ||
+		!reflect.DeepEqual(verr.Problems, want[:2]) {
+		t.Errorf("got %v, want %q", err, want[:2])
+	}
+}
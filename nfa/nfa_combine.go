@@ -0,0 +1,103 @@
+package nfa
+
+import "github.com/paulgriffiths/gods/sets"
+
+// Concat returns an Nfa recognizing the concatenation of the
+// languages of the two provided Nfas. Unlike NewConcatNfa, it places
+// no restrictions on the start or accepting states of its arguments:
+// the states of b follow those of a, and each accepting state of a
+// has an e-transition to the start state of b. The provided Nfas are
+// not modified, and share no state with the result.
+func Concat(a, b Nfa) Nfa {
+	e := append(advanceE(a.epsilons(), 0), advanceE(b.epsilons(), a.Q)...)
+	for _, f := range a.F.Elements() {
+		e[f].Insert(b.Qs + a.Q)
+	}
+
+	return Nfa{
+		Q:  a.Q + b.Q,
+		S:  a.S.Union(b.S),
+		D:  append(advanceD(a.D, 0), advanceD(b.D, a.Q)...),
+		E:  e,
+		Qs: a.Qs,
+		F:  advanceSet(b.F, a.Q),
+	}
+}
+
+// Union returns an Nfa recognizing the union of the languages of the
+// two provided Nfas, which may have any start and accepting states.
+// A new start state 0 has e-transitions to the start states of a and
+// b, whose states follow it, and the accepting states are those of a
+// and b. The provided Nfas are not modified, and share no state with
+// the result.
+func Union(a, b Nfa) Nfa {
+	d := []map[rune]sets.SetInt{{}}
+	d = append(d, advanceD(a.D, 1)...)
+	d = append(d, advanceD(b.D, 1+a.Q)...)
+
+	e := []sets.SetInt{sets.NewSetInt(a.Qs+1, b.Qs+1+a.Q)}
+	e = append(e, advanceE(a.epsilons(), 1)...)
+	e = append(e, advanceE(b.epsilons(), 1+a.Q)...)
+
+	return Nfa{
+		Q:  a.Q + b.Q + 1,
+		S:  a.S.Union(b.S),
+		D:  d,
+		E:  e,
+		Qs: 0,
+		F:  advanceSet(a.F, 1).Union(advanceSet(b.F, 1+a.Q)),
+	}
+}
+
+// Star returns an Nfa recognizing the closure or Kleene star of the
+// language of the provided Nfa, which may have any start and
+// accepting states. A new accepting start state 0 has an e-transition
+// to the start state of n, whose states follow it, and each accepting
+// state of n has an e-transition back to the start state of n. The
+// new start state is needed, rather than making the start state of n
+// accepting, since that state may have incoming transitions. The
+// provided Nfa is not modified, and shares no state with the result.
+func Star(n Nfa) Nfa {
+	d := []map[rune]sets.SetInt{{}}
+	d = append(d, advanceD(n.D, 1)...)
+
+	e := []sets.SetInt{sets.NewSetInt(n.Qs + 1)}
+	e = append(e, advanceE(n.epsilons(), 1)...)
+	for _, f := range n.F.Elements() {
+		e[f+1].Insert(n.Qs + 1)
+	}
+
+	f := advanceSet(n.F, 1)
+	f.Insert(0)
+
+	return Nfa{
+		Q:  n.Q + 1,
+		S:  sets.NewSetRune(n.S.Elements()...),
+		D:  d,
+		E:  e,
+		Qs: 0,
+		F:  f,
+	}
+}
+
+// Plus returns an Nfa recognizing the concatenation of the language
+// of the provided Nfa with its closure, that is, one or more strings
+// from that language. The result has the same states as n, with an
+// additional e-transition from each accepting state back to the
+// start state. The provided Nfa is not modified, and shares no state
+// with the result.
+func Plus(n Nfa) Nfa {
+	e := advanceE(n.epsilons(), 0)
+	for _, f := range n.F.Elements() {
+		e[f].Insert(n.Qs)
+	}
+
+	return Nfa{
+		Q:  n.Q,
+		S:  sets.NewSetRune(n.S.Elements()...),
+		D:  advanceD(n.D, 0),
+		E:  e,
+		Qs: n.Qs,
+		F:  advanceSet(n.F, 0),
+	}
+}
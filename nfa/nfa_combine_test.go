@@ -0,0 +1,183 @@
+package nfa_test
+
+import (
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"github.com/paulgriffiths/gods/sets"
+	"math/rand"
+	"testing"
+)
+
+// randomNfa returns a random Nfa over the alphabet {a, b} with the
+// provided number of states, with any start state, any number of
+// accepting states, and a few e-transitions.
+func randomNfa(r *rand.Rand, q int) nfa.Nfa {
+	n := nfa.Nfa{
+		Q:  q,
+		S:  sets.NewSetRune('a', 'b'),
+		D:  make([]map[rune]sets.SetInt, q),
+		E:  make([]sets.SetInt, q),
+		Qs: r.Intn(q),
+		F:  sets.NewSetInt(),
+	}
+	for i := 0; i < q; i++ {
+		n.D[i] = make(map[rune]sets.SetInt)
+		n.E[i] = sets.NewSetInt()
+		for _, letter := range "ab" {
+			if r.Intn(3) != 0 {
+				n.D[i][letter] = sets.NewSetInt(r.Intn(q))
+				if r.Intn(3) == 0 {
+					n.D[i][letter].Insert(r.Intn(q))
+				}
+			}
+		}
+		if r.Intn(4) == 0 {
+			n.E[i].Insert(r.Intn(q))
+		}
+		if r.Intn(3) == 0 {
+			n.F.Insert(i)
+		}
+	}
+	return n
+}
+
+// acceptsConcat returns true if s can be split into a string
+// accepted by a followed by a string accepted by b.
+func acceptsConcat(a, b nfa.Nfa, s string) bool {
+	for i := 0; i <= len(s); i++ {
+		if a.Accepts(s[:i]) && b.Accepts(s[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsRepeated returns true if s can be split into at least min
+// strings each accepted by n, where min is zero or one. Since the
+// test alphabet is ASCII, byte offsets are rune offsets.
+func acceptsRepeated(n nfa.Nfa, s string, min int) bool {
+	// ok[i] is true if s[:i] can be split into nonempty strings each
+	// accepted by n.
+	ok := make([]bool, len(s)+1)
+	ok[0] = true
+	for i := 1; i <= len(s); i++ {
+		for j := 0; j < i && !ok[i]; j++ {
+			ok[i] = ok[j] && n.Accepts(s[j:i])
+		}
+	}
+	if s == "" {
+		return min == 0 || n.Accepts("")
+	}
+	return ok[len(s)]
+}
+
+func TestCombinatorsRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	inputs := allStrings("ab", 5)
+
+	for i := 0; i < 40; i++ {
+		a := randomNfa(r, 1+r.Intn(4))
+		b := randomNfa(r, 1+r.Intn(4))
+
+		concat, union := nfa.Concat(a, b), nfa.Union(a, b)
+		star, plus := nfa.Star(a), nfa.Plus(a)
+		for _, c := range []nfa.Nfa{concat, union, star, plus} {
+			if err := c.Validate(); err != nil {
+				t.Errorf("case %d, invalid Nfa: %v", i+1, err)
+			}
+		}
+
+		for _, s := range inputs {
+			if got, want := concat.Accepts(s), acceptsConcat(a, b, s); got != want {
+				t.Errorf("case %d, Concat, input %q, got %v, want %v",
+					i+1, s, got, want)
+			}
+			if got, want := union.Accepts(s), a.Accepts(s) || b.Accepts(s); got != want {
+				t.Errorf("case %d, Union, input %q, got %v, want %v",
+					i+1, s, got, want)
+			}
+			if got, want := star.Accepts(s), acceptsRepeated(a, s, 0); got != want {
+				t.Errorf("case %d, Star, input %q, got %v, want %v",
+					i+1, s, got, want)
+			}
+			if got, want := plus.Accepts(s), acceptsRepeated(a, s, 1); got != want {
+				t.Errorf("case %d, Plus, input %q, got %v, want %v",
+					i+1, s, got, want)
+			}
+		}
+	}
+}
+
+func TestCombinatorsAgreeWithNewNfa(t *testing.T) {
+	newAB := func() (nfa.Nfa, nfa.Nfa) {
+		a := nfa.NewUnionNfa(nfa.NewRuneNfa('a'),
+			nfa.NewConcatNfa(nfa.NewRuneNfa('a'), nfa.NewRuneNfa('b')))
+		b := nfa.NewClosureNfa(nfa.NewRuneNfa('b'))
+		return a, b
+	}
+
+	a, b := newAB()
+	testCases := []struct {
+		name      string
+		got, want nfa.Nfa
+	}{
+		{"Concat", nfa.Concat(a, b), nfa.NewConcatNfa(newAB())},
+		{"Union", nfa.Union(a, b), nfa.NewUnionNfa(newAB())},
+		{"Star", nfa.Star(a), nfa.NewClosureNfa(a)},
+		{"Plus", nfa.Plus(a), nfa.NewConcatNfa(a, nfa.NewClosureNfa(a))},
+	}
+
+	for _, tc := range testCases {
+		for _, s := range allStrings("abc", 5) {
+			if got, want := tc.got.Accepts(s), tc.want.Accepts(s); got != want {
+				t.Errorf("%s, input %q, got %v, want %v",
+					tc.name, s, got, want)
+			}
+		}
+	}
+}
+
+func TestCombinatorsGlushkov(t *testing.T) {
+	testCases := []struct {
+		a, b string
+	}{
+		{"a|b", "b*"},
+		{"(ab)*", "a|ba"},
+		{"a*b*", "(ab|b)*a"},
+	}
+
+	glushkov := func(pattern string) nfa.Nfa {
+		node, err := syntax.Parse(pattern)
+		if err != nil {
+			t.Fatalf("couldn't parse %q: %v", pattern, err)
+		}
+		n, err := nfa.FromRegexGlushkov(node)
+		if err != nil {
+			t.Fatalf("couldn't build Nfa for %q: %v", pattern, err)
+		}
+		return n
+	}
+
+	for n, tc := range testCases {
+		// Position automata have any number of accepting states, so
+		// the New...Nfa functions can't be applied to them.
+		a, b := glushkov(tc.a), glushkov(tc.b)
+		pairs := []struct {
+			got, want nfa.Nfa
+		}{
+			{nfa.Concat(a, b), glushkov("(" + tc.a + ")(" + tc.b + ")")},
+			{nfa.Union(a, b), glushkov("(" + tc.a + ")|(" + tc.b + ")")},
+			{nfa.Star(a), glushkov("(" + tc.a + ")*")},
+			{nfa.Plus(b), glushkov("(" + tc.b + ")(" + tc.b + ")*")},
+		}
+
+		for i, p := range pairs {
+			for _, s := range allStrings("ab", 6) {
+				if got, want := p.got.Accepts(s), p.want.Accepts(s); got != want {
+					t.Errorf("case %d.%d, input %q, got %v, want %v",
+						n+1, i+1, s, got, want)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package nfa
+
+import (
+	"github.com/paulgriffiths/automata/internal/dot"
+	"io"
+)
+
+// DotOptions controls the output of WriteDot.
+type DotOptions struct {
+	Name string // Graph name, "nfa" if empty
+}
+
+// WriteDot writes a description of the Nfa in the Graphviz DOT
+// language to w. Accepting states are drawn with a double circle,
+// transitions between the same two states are merged into a single
+// edge whose label lists their runes, with runs of consecutive runes
+// shown as ranges such as a-z, and e-transitions are labeled ε.
+func (n Nfa) WriteDot(w io.Writer, opts DotOptions) error {
+	name := opts.Name
+	if name == "" {
+		name = "nfa"
+	}
+
+	g := dot.New(name)
+	g.Start(n.Qs)
+	for q := 0; q < n.Q; q++ {
+		g.State(q, "", n.F.Contains(q))
+	}
+
+	alphabet := n.alphabet()
+	for from := 0; from < n.Q; from++ {
+		edges := make(map[int][]rune)
+		if from < len(n.D) {
+			for _, letter := range alphabet {
+				for _, to := range n.D[from][letter].Elements() {
+					edges[to] = append(edges[to], letter)
+				}
+			}
+		}
+		for to := 0; to < n.Q; to++ {
+			if letters, ok := edges[to]; ok {
+				g.Edge(from, to, dot.Label(letters))
+			}
+		}
+		if from < len(n.E) {
+			for _, to := range n.E[from].Elements() {
+				g.Edge(from, to, "ε")
+			}
+		}
+	}
+
+	_, err := g.WriteTo(w)
+	return err
+}
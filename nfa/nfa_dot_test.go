@@ -0,0 +1,73 @@
+package nfa_test
+
+import (
+	"bytes"
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/gods/sets"
+	"strings"
+	"testing"
+)
+
+func TestNfaWriteDot(t *testing.T) {
+	n := nfa.Nfa{
+		Q: 3,
+		S: sets.NewSetRune('0', '1', '2', '3'),
+		D: []map[rune]sets.SetInt{
+			{'0': sets.NewSetInt(0, 1), '1': sets.NewSetInt(0, 1)},
+			{'2': sets.NewSetInt(2), '3': sets.NewSetInt(2)},
+			{},
+		},
+		E: []sets.SetInt{
+			sets.NewSetInt(2),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+		},
+		Qs: 0,
+		F:  sets.NewSetInt(2),
+	}
+
+	want := `digraph "nfa" {
+	rankdir=LR;
+	node [shape=circle];
+	start [shape=point];
+	start -> 0;
+	0 [label="0", shape=circle];
+	1 [label="1", shape=circle];
+	2 [label="2", shape=doublecircle];
+	0 -> 0 [label="0,1"];
+	0 -> 1 [label="0,1"];
+	0 -> 2 [label="ε"];
+	1 -> 2 [label="2,3"];
+}
+`
+
+	var b bytes.Buffer
+	if err := n.WriteDot(&b, nfa.DotOptions{}); err != nil {
+		t.Fatalf("couldn't write DOT: %v", err)
+	}
+	if b.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestToDfaStatesWriteDot(t *testing.T) {
+	n := nfa.NewUnionNfa(nfa.NewRuneNfa('a'), nfa.NewRuneNfa('b'))
+	d, states := n.ToDfaStates()
+	if len(states) != d.Q {
+		t.Fatalf("got %d state sets, want %d", len(states), d.Q)
+	}
+	if !states[0].Equals(n.EclosureS(n.Qs)) {
+		t.Errorf("got start state %v, want %v",
+			states[0].Elements(), n.EclosureS(n.Qs).Elements())
+	}
+
+	var b bytes.Buffer
+	opts := dfa.DotOptions{StateSets: states}
+	if err := d.WriteDot(&b, opts); err != nil {
+		t.Fatalf("couldn't write DOT: %v", err)
+	}
+	if want := `0 [label="0\n{0,1,3}", shape=circle];`; !strings.Contains(b.String(), want) {
+		t.Errorf("got\n%s\nwant line %s", b.String(), want)
+	}
+}
@@ -18,18 +18,31 @@ func TestNfaEclosureT1(t *testing.T) {
 		11,
 		sets.NewSetRune('a', 'b'),
 		[]map[rune]sets.SetInt{
-			{0: sets.NewSetInt(1, 7)},
-			{0: sets.NewSetInt(2, 4)},
+			{},
+			{},
 			{'a': sets.NewSetInt(3)},
-			{0: sets.NewSetInt(6)},
+			{},
 			{'b': sets.NewSetInt(5)},
-			{0: sets.NewSetInt(6)},
-			{0: sets.NewSetInt(1, 7)},
+			{},
+			{},
 			{'a': sets.NewSetInt(8)},
 			{'b': sets.NewSetInt(9)},
 			{'b': sets.NewSetInt(10)},
 			{},
 		},
+		[]sets.SetInt{
+			sets.NewSetInt(1, 7),
+			sets.NewSetInt(2, 4),
+			sets.NewSetInt(),
+			sets.NewSetInt(6),
+			sets.NewSetInt(),
+			sets.NewSetInt(6),
+			sets.NewSetInt(1, 7),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+		},
 		0,
 		sets.NewSetInt(10),
 	}
@@ -64,10 +77,16 @@ func TestNfaEclosureT2(t *testing.T) {
 		4,
 		sets.NewSetRune('a', 'b'),
 		[]map[rune]sets.SetInt{
-			{0: sets.NewSetInt(3), 'a': sets.NewSetInt(1)},
-			{0: sets.NewSetInt(0), 'b': sets.NewSetInt(2)},
-			{0: sets.NewSetInt(1), 'b': sets.NewSetInt(3)},
-			{0: sets.NewSetInt(2), 'a': sets.NewSetInt(0)},
+			{'a': sets.NewSetInt(1)},
+			{'b': sets.NewSetInt(2)},
+			{'b': sets.NewSetInt(3)},
+			{'a': sets.NewSetInt(0)},
+		},
+		[]sets.SetInt{
+			sets.NewSetInt(3),
+			sets.NewSetInt(0),
+			sets.NewSetInt(1),
+			sets.NewSetInt(2),
 		},
 		0,
 		sets.NewSetInt(3),
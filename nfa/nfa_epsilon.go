@@ -0,0 +1,52 @@
+package nfa
+
+import "github.com/paulgriffiths/gods/sets"
+
+// RemoveEpsilons returns an equivalent Nfa with no e-transitions.
+// A state of the new Nfa moves on an input symbol to every state
+// its e-closure moves to on that symbol, and is accepting if its
+// e-closure contains an accepting state. States which are no longer
+// reachable from the start state are then discarded, and the
+// remaining states renumbered in the order in which they are
+// reached.
+func (n Nfa) RemoveEpsilons() Nfa {
+	number := map[int]int{n.Qs: 0}
+	order := []int{n.Qs}
+	d := []map[rune]sets.SetInt{}
+	accepts := sets.NewSetInt()
+
+	for i := 0; i < len(order); i++ {
+		ecl := n.EclosureS(order[i])
+		if !n.F.Intersection(ecl).IsEmpty() {
+			accepts.Insert(i)
+		}
+
+		trans := make(map[rune]sets.SetInt)
+		for _, letter := range n.S.Elements() {
+			next := n.Move(ecl, letter)
+			if next.IsEmpty() {
+				continue
+			}
+			targets := sets.NewSetInt()
+			for _, state := range next.Elements() {
+				j, ok := number[state]
+				if !ok {
+					j = len(order)
+					number[state] = j
+					order = append(order, state)
+				}
+				targets.Insert(j)
+			}
+			trans[letter] = targets
+		}
+		d = append(d, trans)
+	}
+
+	return Nfa{
+		Q:  len(order),
+		S:  n.S,
+		D:  d,
+		Qs: 0,
+		F:  accepts,
+	}
+}
@@ -0,0 +1,73 @@
+package nfa_test
+
+import (
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/gods/sets"
+	"testing"
+)
+
+func TestRemoveEpsilons(t *testing.T) {
+	testCases := []nfa.Nfa{
+		nfa.NewClosureNfa(nfa.NewRuneNfa('a')),
+		nfa.NewUnionNfa(nfa.NewRuneNfa('a'), nfa.NewRuneNfa('b')),
+		nfa.NewConcatNfa(
+			nfa.NewClosureNfa(nfa.NewRuneNfa('a')),
+			nfa.NewClosureNfa(nfa.NewRuneNfa('b')),
+		),
+		nfa.NewConcatNfa(
+			nfa.NewClosureNfa(nfa.NewUnionNfa(
+				nfa.NewRuneNfa('a'), nfa.NewRuneNfa('b'))),
+			nfa.NewConcatNfa(nfa.NewRuneNfa('a'), nfa.NewRuneNfa('b')),
+		),
+		// Compilers, figure 3.34.
+		{
+			11,
+			sets.NewSetRune('a', 'b'),
+			[]map[rune]sets.SetInt{
+				{},
+				{},
+				{'a': sets.NewSetInt(3)},
+				{},
+				{'b': sets.NewSetInt(5)},
+				{},
+				{},
+				{'a': sets.NewSetInt(8)},
+				{'b': sets.NewSetInt(9)},
+				{'b': sets.NewSetInt(10)},
+				{},
+			},
+			[]sets.SetInt{
+				sets.NewSetInt(1, 7),
+				sets.NewSetInt(2, 4),
+				sets.NewSetInt(),
+				sets.NewSetInt(6),
+				sets.NewSetInt(),
+				sets.NewSetInt(6),
+				sets.NewSetInt(1, 7),
+				sets.NewSetInt(),
+				sets.NewSetInt(),
+				sets.NewSetInt(),
+				sets.NewSetInt(),
+			},
+			0,
+			sets.NewSetInt(10),
+		},
+	}
+
+	for n, tc := range testCases {
+		r := tc.RemoveEpsilons()
+		if !epsilonFree(r) {
+			t.Errorf("case %d, Nfa has e-transitions", n+1)
+		}
+		if r.Q > tc.Q {
+			t.Errorf("case %d, got %d states, want at most %d",
+				n+1, r.Q, tc.Q)
+		}
+		for _, s := range allStrings("ab", 6) {
+			if got, want := r.Accepts(s), tc.Accepts(s); got != want {
+				t.Errorf("case %d, input %q, got %t, want %t",
+					n+1, s, got, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package nfa
+
+import (
+	"errors"
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"github.com/paulgriffiths/gods/sets"
+)
+
+// ErrUnsupportedOp is returned when a regular expression contains an
+// operator which cannot be translated to a nondeterministic finite
+// automaton.
+var ErrUnsupportedOp = errors.New("nfa: unsupported regular expression operator")
+
+// glushkov holds the sets computed for a subexpression by the
+// Glushkov construction. Positions are numbered from 1 in the order
+// in which the literals appear in the expression.
+type glushkov struct {
+	nullable    bool
+	first, last sets.SetInt
+}
+
+// FromRegexGlushkov creates an Nfa from a regular expression syntax
+// tree using the Glushkov, or position automaton, construction. The
+// resulting Nfa has no e-transitions and exactly n+1 states, where n
+// is the number of literals in the regular expression. State 0 is
+// the start state, and state i is reached after matching the i-th
+// literal.
+func FromRegexGlushkov(ast *syntax.Node) (Nfa, error) {
+	var symbols []rune
+	follow := []sets.SetInt{sets.NewSetInt()}
+
+	var visit func(*syntax.Node) (glushkov, error)
+	visit = func(n *syntax.Node) (glushkov, error) {
+		switch n.Op {
+		case syntax.OpNoMatch:
+			return glushkov{false, sets.NewSetInt(), sets.NewSetInt()}, nil
+		case syntax.OpEmptyMatch:
+			return glushkov{true, sets.NewSetInt(), sets.NewSetInt()}, nil
+		case syntax.OpLiteral:
+			symbols = append(symbols, n.Rune)
+			follow = append(follow, sets.NewSetInt())
+			p := len(symbols)
+			return glushkov{false, sets.NewSetInt(p), sets.NewSetInt(p)}, nil
+		case syntax.OpConcat:
+			result := glushkov{true, sets.NewSetInt(), sets.NewSetInt()}
+			for _, sub := range n.Sub {
+				g, err := visit(sub)
+				if err != nil {
+					return glushkov{}, err
+				}
+				for _, p := range result.last.Elements() {
+					follow[p].Merge(g.first)
+				}
+				if result.nullable {
+					result.first.Merge(g.first)
+				}
+				if g.nullable {
+					result.last.Merge(g.last)
+				} else {
+					result.last = g.last
+				}
+				result.nullable = result.nullable && g.nullable
+			}
+			return result, nil
+		case syntax.OpAlternate:
+			result := glushkov{false, sets.NewSetInt(), sets.NewSetInt()}
+			for _, sub := range n.Sub {
+				g, err := visit(sub)
+				if err != nil {
+					return glushkov{}, err
+				}
+				result.nullable = result.nullable || g.nullable
+				result.first.Merge(g.first)
+				result.last.Merge(g.last)
+			}
+			return result, nil
+		case syntax.OpGroup:
+			return visit(n.Sub[0])
+		case syntax.OpStar:
+			g, err := visit(n.Sub[0])
+			if err != nil {
+				return glushkov{}, err
+			}
+			for _, p := range g.last.Elements() {
+				follow[p].Merge(g.first)
+			}
+			g.nullable = true
+			return g, nil
+		}
+		return glushkov{}, ErrUnsupportedOp
+	}
+
+	g, err := visit(ast)
+	if err != nil {
+		return Nfa{}, err
+	}
+
+	// The start state behaves as a position which is followed by
+	// the first positions of the whole expression.
+	follow[0] = g.first
+	accepts := g.last
+	if g.nullable {
+		accepts.Insert(0)
+	}
+
+	d := make([]map[rune]sets.SetInt, len(symbols)+1)
+	for q := range d {
+		d[q] = make(map[rune]sets.SetInt)
+		for _, p := range follow[q].Elements() {
+			letter := symbols[p-1]
+			if _, ok := d[q][letter]; !ok {
+				d[q][letter] = sets.NewSetInt()
+			}
+			d[q][letter].Insert(p)
+		}
+	}
+
+	return Nfa{
+		Q:  len(symbols) + 1,
+		S:  sets.NewSetRune(symbols...),
+		D:  d,
+		Qs: 0,
+		F:  accepts,
+	}, nil
+}
@@ -0,0 +1,90 @@
+package nfa_test
+
+import (
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/automata/regex"
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"testing"
+)
+
+// allStrings returns all strings over the provided alphabet with
+// lengths up to and including n.
+func allStrings(alphabet string, n int) []string {
+	result := []string{""}
+	last := []string{""}
+	for i := 0; i < n; i++ {
+		next := []string{}
+		for _, s := range last {
+			for _, r := range alphabet {
+				next = append(next, s+string(r))
+			}
+		}
+		result = append(result, next...)
+		last = next
+	}
+	return result
+}
+
+// epsilonFree returns true if the Nfa has no e-transitions.
+func epsilonFree(n nfa.Nfa) bool {
+	for _, e := range n.E {
+		if !e.IsEmpty() {
+			return false
+		}
+	}
+	return true
+}
+
+func TestGlushkov(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		states  int
+	}{
+		{"a", 2},
+		{"ab", 3},
+		{"a*", 2},
+		{"a|b", 3},
+		{"(a|b)*abb", 6},
+		{"aa(a|b)*bb", 7},
+		{"a*b*", 3},
+		{"((aa|bb)(aa|bb))*", 9},
+		{"(a*b*)*", 3},
+		{"((a|b)(1|2)*(a|b))*", 7},
+	}
+
+	for n, tc := range testCases {
+		ast, err := syntax.Parse(tc.pattern)
+		if err != nil {
+			t.Fatalf("case %d, couldn't parse %q: %v", n+1, tc.pattern, err)
+		}
+		g, err := nfa.FromRegexGlushkov(ast)
+		if err != nil {
+			t.Errorf("case %d, couldn't build Nfa: %v", n+1, err)
+			continue
+		}
+		if g.Q != tc.states {
+			t.Errorf("case %d, got %d states, want %d", n+1, g.Q, tc.states)
+		}
+		if !epsilonFree(g) {
+			t.Errorf("case %d, Nfa has e-transitions", n+1)
+		}
+
+		r := regex.Compile(tc.pattern)
+		for _, s := range allStrings("ab12", 5) {
+			if got, want := g.Accepts(s), r.Match(s); got != want {
+				t.Errorf("case %d, input %q, got %t, want %t",
+					n+1, s, got, want)
+			}
+		}
+	}
+}
+
+func TestGlushkovUnsupported(t *testing.T) {
+	ast, err := syntax.Parse("a&b")
+	if err != nil {
+		t.Fatalf("couldn't parse pattern: %v", err)
+	}
+	if _, err := nfa.FromRegexGlushkov(ast); err != nfa.ErrUnsupportedOp {
+		t.Errorf("got %v, want %v", err, nfa.ErrUnsupportedOp)
+	}
+}
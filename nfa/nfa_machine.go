@@ -0,0 +1,128 @@
+package nfa
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/gods/sets"
+)
+
+// Machine is a nondeterministic finite automaton over an alphabet of
+// symbols of any comparable type, such as an enumeration of events.
+// It has the same fields as an Nfa, except that the alphabet is a
+// slice, which should not hold duplicates, and every symbol with a
+// transition should be in it. An Nfa is a Machine over runes, and
+// its methods are implemented by the corresponding methods of the
+// Machine returned by FromNfa.
+type Machine[T comparable] struct {
+	Q  int                 // Number of states
+	S  []T                 // Alphabet
+	D  []map[T]sets.SetInt // Transition function
+	E  []sets.SetInt       // e-transitions, may be nil if none
+	Qs int                 // Start state
+	F  sets.SetInt         // Set of accepting states
+}
+
+// Accepts returns true if the Machine accepts the provided sequence
+// of symbols.
+func (m Machine[T]) Accepts(input []T) bool {
+	current := m.EclosureS(m.Qs)
+	for _, symbol := range input {
+		if current = m.step(current, symbol); current.IsEmpty() {
+			return false
+		}
+	}
+	return !m.F.Intersection(current).IsEmpty()
+}
+
+// step returns the set of states which the Machine can be in after
+// reading the provided symbol from any of the states in current,
+// including those reachable afterwards on e-transitions.
+func (m Machine[T]) step(current sets.SetInt, symbol T) sets.SetInt {
+	return m.EclosureT(m.Move(current, symbol))
+}
+
+// EclosureS returns the set of states reachable from the specified
+// state on e-transitions alone, as described for Nfa.EclosureS.
+func (m Machine[T]) EclosureS(s int) sets.SetInt {
+	current := sets.NewSetInt(s)
+	ecl := current
+	prevLength := -1
+
+	for ecl.Length() != prevLength {
+		prevLength = ecl.Length()
+		next := sets.NewSetInt()
+		for _, state := range current.Elements() {
+			if state < len(m.E) {
+				ecl.Merge(m.E[state])
+				next.Merge(m.E[state])
+			}
+		}
+		current = next
+	}
+
+	return ecl
+}
+
+// EclosureT returns the set of states reachable from the provided
+// set of states on e-transitions alone.
+func (m Machine[T]) EclosureT(t sets.SetInt) sets.SetInt {
+	ecl := sets.NewSetInt()
+	for _, state := range t.Elements() {
+		ecl.Merge(m.EclosureS(state))
+	}
+	return ecl
+}
+
+// Move returns the set of states reachable from set t on the
+// provided symbol.
+func (m Machine[T]) Move(t sets.SetInt, symbol T) sets.SetInt {
+	trans := sets.NewSetInt()
+	for _, state := range t.Elements() {
+		if p, ok := m.D[state][symbol]; ok {
+			trans.Merge(p)
+		}
+	}
+	return trans
+}
+
+// ToDfa converts the Machine to a deterministic Machine using the
+// subset construction, as described for Nfa.ToDfa. The alphabet of
+// the result is the alphabet of the Machine. It panics if the
+// Machine has a transition on a symbol outside its alphabet.
+func (m Machine[T]) ToDfa() dfa.Machine[T] {
+	alphabet := make(map[T]bool, len(m.S))
+	for _, symbol := range m.S {
+		alphabet[symbol] = true
+	}
+	for q, trans := range m.D {
+		for symbol := range trans {
+			if !alphabet[symbol] {
+				panic(fmt.Sprintf("nfa: state %d, symbol %v: symbol not in alphabet", q, symbol))
+			}
+		}
+	}
+
+	d, _ := m.toDfaStates()
+	return d
+}
+
+// FromNfa returns the Nfa as a Machine over runes, sharing its
+// transitions and accepting states. The alphabet of the Machine is
+// the Nfa's, together with any other runes on which it has
+// transitions, in ascending order.
+func FromNfa(n Nfa) Machine[rune] {
+	return n.machine(n.alphabet())
+}
+
+// ToNfa returns the Machine over runes as an Nfa, sharing its
+// transitions and accepting states.
+func ToNfa(m Machine[rune]) Nfa {
+	return Nfa{
+		Q:  m.Q,
+		S:  sets.NewSetRune(m.S...),
+		D:  m.D,
+		E:  m.E,
+		Qs: m.Qs,
+		F:  m.F,
+	}
+}
@@ -0,0 +1,128 @@
+package nfa_test
+
+import (
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/gods/sets"
+	"testing"
+)
+
+type event string
+
+// allSequences returns all the sequences of the provided symbols with
+// length at most n.
+func allSequences[T any](symbols []T, n int) [][]T {
+	result := [][]T{{}}
+	last := [][]T{{}}
+	for i := 0; i < n; i++ {
+		next := [][]T{}
+		for _, s := range last {
+			for _, symbol := range symbols {
+				next = append(next, append(append([]T{}, s...), symbol))
+			}
+		}
+		result = append(result, next...)
+		last = next
+	}
+	return result
+}
+
+// retries accepts sequences of events ending in an ack, or in a
+// timeout followed by a retry and an ack.
+var retries = nfa.Machine[event]{
+	Q: 5,
+	S: []event{"ack", "timeout", "retry"},
+	D: []map[event]sets.SetInt{
+		{"ack": sets.NewSetInt(0), "timeout": sets.NewSetInt(0), "retry": sets.NewSetInt(0)},
+		{"ack": sets.NewSetInt(4), "timeout": sets.NewSetInt(2)},
+		{"retry": sets.NewSetInt(3)},
+		{"ack": sets.NewSetInt(4)},
+		{},
+	},
+	E: []sets.SetInt{
+		sets.NewSetInt(1),
+		sets.NewSetInt(),
+		sets.NewSetInt(),
+		sets.NewSetInt(),
+		sets.NewSetInt(),
+	},
+	Qs: 0,
+	F:  sets.NewSetInt(4),
+}
+
+func TestMachineAccepts(t *testing.T) {
+	testCases := []struct {
+		input []event
+		want  bool
+	}{
+		{[]event{"ack"}, true},
+		{[]event{"retry", "timeout", "retry", "ack"}, true},
+		{[]event{"timeout", "retry"}, false},
+		{[]event{"ack", "ack", "timeout"}, false},
+		{[]event{"nack"}, false},
+		{[]event{}, false},
+	}
+
+	for n, tc := range testCases {
+		if got := retries.Accepts(tc.input); got != tc.want {
+			t.Errorf("case %d, got %t, want %t", n+1, got, tc.want)
+		}
+	}
+}
+
+func TestMachineToDfa(t *testing.T) {
+	d := retries.ToDfa()
+	m := d.Minimize()
+	for _, s := range allSequences(retries.S, 5) {
+		want := retries.Accepts(s)
+		if got := d.Accepts(s); got != want {
+			t.Errorf("sequence %v, got %t, want %t", s, got, want)
+		}
+		if got := m.Accepts(s); got != want {
+			t.Errorf("sequence %v, got minimized %t, want %t", s, got, want)
+		}
+	}
+	if len(d.S) != len(retries.S) {
+		t.Errorf("got alphabet %v, want %v", d.S, retries.S)
+	}
+}
+
+func TestMachineConversion(t *testing.T) {
+	m := nfa.FromNfa(nfa3)
+	if got, want := string(m.S), "ab"; got != want {
+		t.Errorf("got alphabet %q, want %q", got, want)
+	}
+	d := nfa3.ToDfa()
+	md := m.ToDfa()
+	for _, s := range allStrings("ab", 6) {
+		want := nfa3.Accepts(s)
+		if got := m.Accepts([]rune(s)); got != want {
+			t.Errorf("string %q, got %t, want %t", s, got, want)
+		}
+		if got := md.Accepts([]rune(s)); got != d.Accepts(s) {
+			t.Errorf("string %q, got %t from Dfa, want %t", s, got, want)
+		}
+	}
+	if !equalNfas(nfa.ToNfa(m), nfa3) {
+		t.Errorf("round trip changed automaton")
+	}
+}
+
+func TestMachineToDfaOutsideAlphabet(t *testing.T) {
+	m := nfa.Machine[event]{
+		Q: 2,
+		S: []event{"ack"},
+		D: []map[event]sets.SetInt{
+			{"ack": sets.NewSetInt(1), "retry": sets.NewSetInt(0)},
+			{},
+		},
+		Qs: 0,
+		F:  sets.NewSetInt(1),
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("ToDfa didn't panic")
+		}
+	}()
+	m.ToDfa()
+}
@@ -0,0 +1,242 @@
+package nfa
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/paulgriffiths/automata/internal/table"
+	"github.com/paulgriffiths/gods/sets"
+	"unicode/utf8"
+)
+
+// jsonNfa is the JSON representation of an Nfa. Runes are written
+// as single-character strings, and the transitions of each state as
+// an object mapping those strings to sets of states, so that
+// encoding/json writes them in a stable order.
+type jsonNfa struct {
+	States      int                `json:"states"`
+	Alphabet    []string           `json:"alphabet"`
+	Start       int                `json:"start"`
+	Accepting   []int              `json:"accepting"`
+	Transitions []map[string][]int `json:"transitions"`
+	Epsilons    [][]int            `json:"epsilons"`
+}
+
+// MarshalJSON returns the JSON encoding of the Nfa.
+func (n Nfa) MarshalJSON() ([]byte, error) {
+	j := jsonNfa{
+		States:      n.Q,
+		Alphabet:    []string{},
+		Start:       n.Qs,
+		Accepting:   n.F.Elements(),
+		Transitions: make([]map[string][]int, len(n.D)),
+		Epsilons:    make([][]int, len(n.E)),
+	}
+
+	for _, letter := range n.alphabet() {
+		j.Alphabet = append(j.Alphabet, string(letter))
+	}
+	for q, trans := range n.D {
+		j.Transitions[q] = make(map[string][]int)
+		for letter, states := range trans {
+			j.Transitions[q][string(letter)] = states.Elements()
+		}
+	}
+	for q, states := range n.E {
+		j.Epsilons[q] = states.Elements()
+	}
+
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON sets the Nfa to the one described by the provided
+// JSON encoding.
+// If that Nfa is not well-formed, the error is a *ValidationError.
+func (n *Nfa) UnmarshalJSON(data []byte) error {
+	j := jsonNfa{}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return err
+	}
+
+	result := Nfa{
+		Q:  j.States,
+		S:  sets.NewSetRune(),
+		D:  make([]map[rune]sets.SetInt, len(j.Transitions)),
+		Qs: j.Start,
+		F:  sets.NewSetInt(j.Accepting...),
+	}
+
+	for _, s := range j.Alphabet {
+		letter, err := jsonRune(s)
+		if err != nil {
+			return err
+		}
+		result.S.Insert(letter)
+	}
+	for q, trans := range j.Transitions {
+		result.D[q] = make(map[rune]sets.SetInt)
+		for s, states := range trans {
+			letter, err := jsonRune(s)
+			if err != nil {
+				return err
+			}
+			result.D[q][letter] = sets.NewSetInt(states...)
+		}
+	}
+	if j.Epsilons != nil {
+		result.E = make([]sets.SetInt, len(j.Epsilons))
+		for q, states := range j.Epsilons {
+			result.E[q] = sets.NewSetInt(states...)
+		}
+	}
+
+	if err := result.Validate(); err != nil {
+		return err
+	}
+
+	*n = result
+	return nil
+}
+
+// jsonRune converts a single-character string to a rune.
+func jsonRune(s string) (rune, error) {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 || size != len(s) || r == utf8.RuneError && size == 1 {
+		return 0, fmt.Errorf("nfa: bad rune %q", s)
+	}
+	return r, nil
+}
+
+// MarshalText returns the Nfa as a human-readable state table, for
+// example:
+//
+//	nfa
+//	      'a'    'b'  ε
+//	>  0  {0,1}  {0}  -
+//	   1  -      {2}  {0}
+//	*  2  -      -    -
+//
+// The header lists the alphabet, followed by ε if the Nfa has any
+// e-transitions, and each row gives a state, marked with > if it is
+// the start state and * if it is accepting, followed by the set of
+// states moved to on each letter, or - if there are none.
+func (n Nfa) MarshalText() ([]byte, error) {
+	alphabet := n.alphabet()
+	t := table.Table{Kind: "nfa"}
+	for _, letter := range alphabet {
+		t.Header = append(t.Header, table.FormatRune(letter))
+	}
+
+	epsilons := false
+	for _, states := range n.E {
+		epsilons = epsilons || !states.IsEmpty()
+	}
+	if epsilons {
+		t.Header = append(t.Header, table.Epsilon)
+	}
+
+	for q := 0; q < n.Q; q++ {
+		row := table.Row{
+			Start:     q == n.Qs,
+			Accepting: n.F.Contains(q),
+			State:     q,
+		}
+		for _, letter := range alphabet {
+			var states []int
+			if q < len(n.D) {
+				states = n.D[q][letter].Elements()
+			}
+			row.Cells = append(row.Cells, table.FormatSet(states))
+		}
+		if epsilons {
+			var states []int
+			if q < len(n.E) {
+				states = n.E[q].Elements()
+			}
+			row.Cells = append(row.Cells, table.FormatSet(states))
+		}
+		t.Rows = append(t.Rows, row)
+	}
+
+	return table.Write(t), nil
+}
+
+// UnmarshalText sets the Nfa to the one described by the provided
+// state table, in the format written by MarshalText. The rows may
+// appear in any order, but there must be exactly one for each state.
+// If the Nfa is not well-formed, the error is a *ValidationError.
+func (n *Nfa) UnmarshalText(text []byte) error {
+	t, err := table.Read(text, "nfa")
+	if err != nil {
+		return fmt.Errorf("nfa: %v", err)
+	}
+
+	result := Nfa{
+		Q:  len(t.Rows),
+		S:  sets.NewSetRune(),
+		D:  make([]map[rune]sets.SetInt, len(t.Rows)),
+		E:  make([]sets.SetInt, len(t.Rows)),
+		Qs: -1,
+		F:  sets.NewSetInt(),
+	}
+
+	// A column index of -1 holds the e-transitions.
+	columns := []rune{}
+	epsilon := -1
+	for i, token := range t.Header {
+		if token == table.Epsilon {
+			epsilon = i
+			columns = append(columns, 0)
+			continue
+		}
+		letter, err := table.ParseRune(token)
+		if err != nil {
+			return fmt.Errorf("nfa: %v", err)
+		}
+		columns = append(columns, letter)
+		result.S.Insert(letter)
+	}
+
+	for _, row := range t.Rows {
+		if row.State < 0 || row.State >= result.Q {
+			return fmt.Errorf("nfa: state %d out of range", row.State)
+		}
+		if result.D[row.State] != nil {
+			return fmt.Errorf("nfa: duplicate state %d", row.State)
+		}
+		if row.Start {
+			if result.Qs != -1 {
+				return fmt.Errorf("nfa: more than one start state")
+			}
+			result.Qs = row.State
+		}
+		if row.Accepting {
+			result.F.Insert(row.State)
+		}
+
+		result.D[row.State] = make(map[rune]sets.SetInt)
+		result.E[row.State] = sets.NewSetInt()
+		for i, cell := range row.Cells {
+			states, err := table.ParseSet(cell)
+			if err != nil {
+				return fmt.Errorf("nfa: state %d: %v", row.State, err)
+			}
+			switch {
+			case i == epsilon:
+				result.E[row.State].Insert(states...)
+			case states != nil:
+				result.D[row.State][columns[i]] = sets.NewSetInt(states...)
+			}
+		}
+	}
+
+	if result.Qs == -1 {
+		return fmt.Errorf("nfa: missing start state")
+	}
+
+	if err := result.Validate(); err != nil {
+		return err
+	}
+
+	*n = result
+	return nil
+}
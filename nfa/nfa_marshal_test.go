@@ -0,0 +1,138 @@
+package nfa_test
+
+import (
+	"encoding/json"
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/gods/sets"
+	"testing"
+)
+
+// Recognizes strings containing at least 2 'a's and ending with
+// a 'b'.
+// Compilers, figure 3.29.
+var nfa3 = nfa.Nfa{
+	Q: 4,
+	S: sets.NewSetRune('a', 'b'),
+	D: []map[rune]sets.SetInt{
+		{'a': sets.NewSetInt(0, 1), 'b': sets.NewSetInt(0)},
+		{'a': sets.NewSetInt(1, 2), 'b': sets.NewSetInt(1)},
+		{'a': sets.NewSetInt(2), 'b': sets.NewSetInt(2, 3)},
+		{},
+	},
+	E: []sets.SetInt{
+		sets.NewSetInt(),
+		sets.NewSetInt(),
+		sets.NewSetInt(0),
+		sets.NewSetInt(),
+	},
+	Qs: 0,
+	F:  sets.NewSetInt(3),
+}
+
+// equalNfas returns true if two Nfas have identical definitions.
+func equalNfas(a, b nfa.Nfa) bool {
+	if a.Q != b.Q || a.Qs != b.Qs || !a.F.Equals(b.F) ||
+		!a.S.Equals(b.S) || len(a.D) != len(b.D) || len(a.E) != len(b.E) {
+		return false
+	}
+	for q := range a.D {
+		if len(a.D[q]) != len(b.D[q]) || !a.E[q].Equals(b.E[q]) {
+			return false
+		}
+		for letter, states := range a.D[q] {
+			if !states.Equals(b.D[q][letter]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestNfaMarshalJSON(t *testing.T) {
+	want := `{"states":4,"alphabet":["a","b"],"start":0,"accepting":[3],` +
+		`"transitions":[{"a":[0,1],"b":[0]},{"a":[1,2],"b":[1]},` +
+		`{"a":[2],"b":[2,3]},{}],"epsilons":[[],[],[0],[]]}`
+
+	b, err := json.Marshal(nfa3)
+	if err != nil {
+		t.Fatalf("couldn't marshal Nfa: %v", err)
+	}
+	if string(b) != want {
+		t.Errorf("got %s, want %s", b, want)
+	}
+
+	var n nfa.Nfa
+	if err := json.Unmarshal(b, &n); err != nil {
+		t.Fatalf("couldn't unmarshal Nfa: %v", err)
+	}
+	if !equalNfas(n, nfa3) {
+		t.Errorf("got %v, want %v", n, nfa3)
+	}
+}
+
+func TestNfaMarshalText(t *testing.T) {
+	want := `nfa
+      'a'    'b'    ε
+>  0  {0,1}  {0}    -
+   1  {1,2}  {1}    -
+   2  {2}    {2,3}  {0}
+*  3  -      -      -
+`
+
+	b, err := nfa3.MarshalText()
+	if err != nil {
+		t.Fatalf("couldn't marshal Nfa: %v", err)
+	}
+	if string(b) != want {
+		t.Errorf("got\n%s\nwant\n%s", b, want)
+	}
+
+	var n nfa.Nfa
+	if err := n.UnmarshalText(b); err != nil {
+		t.Fatalf("couldn't unmarshal Nfa: %v", err)
+	}
+	if !equalNfas(n, nfa3) {
+		t.Errorf("got %v, want %v", n, nfa3)
+	}
+}
+
+func TestNfaMarshalTextEmptyAlphabet(t *testing.T) {
+	want := nfa.Nfa{
+		Q:  1,
+		S:  sets.NewSetRune(),
+		D:  []map[rune]sets.SetInt{{}},
+		E:  []sets.SetInt{sets.NewSetInt()},
+		Qs: 0,
+		F:  sets.NewSetInt(0),
+	}
+
+	b, err := want.MarshalText()
+	if err != nil {
+		t.Fatalf("couldn't marshal Nfa: %v", err)
+	}
+	var n nfa.Nfa
+	if err := n.UnmarshalText(b); err != nil {
+		t.Fatalf("couldn't unmarshal %q: %v", b, err)
+	}
+	if !equalNfas(n, want) {
+		t.Errorf("got %v, want %v", n, want)
+	}
+}
+
+func TestNfaUnmarshalTextErrors(t *testing.T) {
+	testCases := []string{
+		"",
+		"dfa\n'a'\n>0 -\n",
+		"nfa\n'a'\n0 -\n",
+		"nfa\n'a'\n>0 {0\n",
+		"nfa\n'a'\n>0 {x}\n",
+		"nfa\n'a' ε\n>0 {0}\n",
+	}
+
+	for n, tc := range testCases {
+		var a nfa.Nfa
+		if err := a.UnmarshalText([]byte(tc)); err == nil {
+			t.Errorf("case %d, unexpectedly unmarshaled %q", n+1, tc)
+		}
+	}
+}
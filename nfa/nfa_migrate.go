@@ -0,0 +1,48 @@
+package nfa
+
+import "github.com/paulgriffiths/gods/sets"
+
+// MigrateEpsilons converts an Nfa written for earlier versions of
+// this package, in which e-transitions were represented as
+// transitions on the rune 0 in D, to the current representation.
+// It returns a copy of the Nfa with any transitions on rune 0 moved
+// from D to E, and with rune 0 removed from the alphabet. The
+// provided Nfa is not modified.
+//
+// Nfas which really do need to match the null character must not be
+// passed to this function.
+func MigrateEpsilons(n Nfa) Nfa {
+	e := n.epsilons()
+	migrated := Nfa{
+		Q:  n.Q,
+		S:  sets.NewSetRune(),
+		D:  make([]map[rune]sets.SetInt, len(n.D)),
+		E:  make([]sets.SetInt, n.Q),
+		Qs: n.Qs,
+		F:  n.F,
+	}
+
+	for _, letter := range n.S.Elements() {
+		if letter != 0 {
+			migrated.S.Insert(letter)
+		}
+	}
+
+	for i := range migrated.E {
+		migrated.E[i] = sets.NewSetInt()
+		migrated.E[i].Merge(e[i])
+	}
+
+	for i, trans := range n.D {
+		migrated.D[i] = make(map[rune]sets.SetInt)
+		for letter, states := range trans {
+			if letter == 0 {
+				migrated.E[i].Merge(states)
+			} else {
+				migrated.D[i][letter] = states
+			}
+		}
+	}
+
+	return migrated
+}
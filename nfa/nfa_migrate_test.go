@@ -0,0 +1,103 @@
+package nfa_test
+
+import (
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/gods/sets"
+	"testing"
+)
+
+func TestMigrateEpsilons(t *testing.T) {
+	// Recognizes aa*|bb*, with e-transitions on rune 0.
+	// Compilers, figure 3.26.
+	legacy := nfa.Nfa{
+		Q: 5,
+		S: sets.NewSetRune(0, 'a', 'b'),
+		D: []map[rune]sets.SetInt{
+			{0: sets.NewSetInt(1, 3)},
+			{'a': sets.NewSetInt(2)},
+			{'a': sets.NewSetInt(2)},
+			{'b': sets.NewSetInt(4)},
+			{'b': sets.NewSetInt(4)},
+		},
+		Qs: 0,
+		F:  sets.NewSetInt(2, 4),
+	}
+	n := nfa.MigrateEpsilons(legacy)
+
+	if _, ok := legacy.D[0][0]; !ok {
+		t.Errorf("legacy Nfa was modified")
+	}
+	if _, ok := n.D[0][0]; ok {
+		t.Errorf("migrated Nfa has transition on rune 0")
+	}
+	if n.S.Contains(0) {
+		t.Errorf("migrated Nfa has rune 0 in alphabet")
+	}
+	if !n.E[0].Equals(sets.NewSetInt(1, 3)) {
+		t.Errorf("got e-transitions %v, want %v",
+			n.E[0].Elements(), []int{1, 3})
+	}
+
+	testCases := []struct {
+		input  string
+		result bool
+	}{
+		{"", false},
+		{"a", true},
+		{"bbb", true},
+		{"ab", false},
+		{"\x00a", false},
+	}
+
+	for _, tc := range testCases {
+		if r := n.Accepts(tc.input); r != tc.result {
+			t.Errorf("input %q, got %v, want %v", tc.input, r, tc.result)
+		}
+	}
+}
+
+func TestNullRune(t *testing.T) {
+	// Recognizes a NUL followed by any number of 'a's, or the
+	// empty string via an e-transition.
+	n := nfa.Nfa{
+		Q: 3,
+		S: sets.NewSetRune(0, 'a'),
+		D: []map[rune]sets.SetInt{
+			{0: sets.NewSetInt(1)},
+			{'a': sets.NewSetInt(1)},
+			{},
+		},
+		E: []sets.SetInt{
+			sets.NewSetInt(2),
+			sets.NewSetInt(),
+			sets.NewSetInt(),
+		},
+		Qs: 0,
+		F:  sets.NewSetInt(1, 2),
+	}
+
+	testCases := []struct {
+		input  string
+		result bool
+	}{
+		{"", true},
+		{"\x00", true},
+		{"\x00aaa", true},
+		{"a", false},
+		{"\x00\x00", false},
+	}
+
+	for _, tc := range testCases {
+		if r := n.Accepts(tc.input); r != tc.result {
+			t.Errorf("input %q, got %v, want %v", tc.input, r, tc.result)
+		}
+		if r := n.ToDfa().Accepts(tc.input); r != tc.result {
+			t.Errorf("input %q, got %v from DFA, want %v",
+				tc.input, r, tc.result)
+		}
+	}
+
+	if got := n.Move(sets.NewSetInt(0), 0); !got.Equals(sets.NewSetInt(1)) {
+		t.Errorf("Move on rune 0, got %v, want %v", got.Elements(), []int{1})
+	}
+}
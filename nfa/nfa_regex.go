@@ -9,13 +9,16 @@ func NewRuneNfa(r rune) Nfa {
 		Q:  2,
 		S:  sets.NewSetRune(r),
 		D:  []map[rune]sets.SetInt{{r: sets.NewSetInt(1)}, {}},
+		E:  []sets.SetInt{sets.NewSetInt(), sets.NewSetInt()},
 		Qs: 0,
 		F:  sets.NewSetInt(1),
 	}
 }
 
 // NewConcatNfa creates an Nfa representing the concatenation
-// of the two provided Nfas.
+// of the two provided Nfas. The provided Nfas are not modified, and
+// share no state with the result, so the same Nfa may safely be
+// provided more than once.
 // Note: this function assumes that the final state is the single
 // accepting state for both provided Nfas. All the New...Nfa functions
 // in this file create Nfas that satisfy that condition, so it's
@@ -25,59 +28,92 @@ func NewConcatNfa(a, b Nfa) Nfa {
 	return Nfa{
 		Q:  a.Q + b.Q - 1,
 		S:  a.S.Union(b.S),
-		D:  append(a.D[:a.Q-1], advanceD(b.D, a.Q-1)...),
+		D:  append(advanceD(a.D[:a.Q-1], 0), advanceD(b.D, a.Q-1)...),
+		E:  append(advanceE(a.epsilons()[:a.Q-1], 0), advanceE(b.epsilons(), a.Q-1)...),
 		Qs: 0,
 		F:  advanceSet(b.F, a.Q-1),
 	}
 }
 
 // NewUnionNfa creates an Nfa representing the union
-// of the two provided Nfas.
+// of the two provided Nfas. The provided Nfas are not modified, and
+// share no state with the result, so the same Nfa may safely be
+// provided more than once.
 // Note: this function assumes that the final state is the single
 // accepting state for both provided Nfas. All the New...Nfa functions
 // in this file create Nfas that satisfy that condition, so it's
 // safe to rely on the assumption if these and only these functions
 // are used together.
 func NewUnionNfa(a, b Nfa) Nfa {
-	dA := advanceD(a.D, 1)
-	dB := advanceD(b.D, 1+a.Q)
-	dA[a.Q-1][0] = sets.NewSetInt(a.Q + b.Q + 1)
-	dB[b.Q-1][0] = sets.NewSetInt(a.Q + b.Q + 1)
-	dJ := []map[rune]sets.SetInt{{0: sets.NewSetInt(1, a.Q+1)}}
-	dJ = append(dJ, dA...)
-	dJ = append(dJ, dB...)
+	dJ := []map[rune]sets.SetInt{{}}
+	dJ = append(dJ, advanceD(a.D, 1)...)
+	dJ = append(dJ, advanceD(b.D, 1+a.Q)...)
 	dJ = append(dJ, map[rune]sets.SetInt{})
 
+	eA := advanceE(a.epsilons(), 1)
+	eB := advanceE(b.epsilons(), 1+a.Q)
+	eA[a.Q-1].Insert(a.Q + b.Q + 1)
+	eB[b.Q-1].Insert(a.Q + b.Q + 1)
+	eJ := []sets.SetInt{sets.NewSetInt(1, a.Q+1)}
+	eJ = append(eJ, eA...)
+	eJ = append(eJ, eB...)
+	eJ = append(eJ, sets.NewSetInt())
+
 	return Nfa{
 		Q:  a.Q + b.Q + 2,
 		S:  a.S.Union(b.S),
 		D:  dJ,
+		E:  eJ,
 		Qs: 0,
 		F:  sets.NewSetInt(a.Q + b.Q + 1),
 	}
 }
 
 // NewClosureNfa creates an Nfa representing the closure or Kleene
-// star operation of the provided Nfa.
+// star operation of the provided Nfa. The provided Nfa is not
+// modified, and shares no state with the result.
 // Note: this function assumes that the final state is the single
 // accepting state for the provided Nfa. All the New...Nfa functions
 // in this file create Nfas that satisfy that condition, so it's
 // safe to rely on the assumption if these and only these functions
 // are used together.
 func NewClosureNfa(n Nfa) Nfa {
-	d := []map[rune]sets.SetInt{{0: sets.NewSetInt(1, n.Q+1)}}
+	d := []map[rune]sets.SetInt{{}}
 	d = append(d, advanceD(n.D, 1)...)
 	d = append(d, map[rune]sets.SetInt{})
-	d[n.Q][0] = sets.NewSetInt(1, n.Q+1)
+
+	e := []sets.SetInt{sets.NewSetInt(1, n.Q+1)}
+	e = append(e, advanceE(n.epsilons(), 1)...)
+	e = append(e, sets.NewSetInt())
+	e[n.Q].Insert(1, n.Q+1)
+
 	return Nfa{
 		Q:  n.Q + 2,
-		S:  n.S,
+		S:  sets.NewSetRune(n.S.Elements()...),
 		D:  d,
+		E:  e,
 		Qs: 0,
 		F:  sets.NewSetInt(n.Q + 1),
 	}
 }
 
+// epsilons returns the e-transitions of the Nfa with one set for
+// each state, creating empty sets for any states for which E has
+// no entry.
+func (n Nfa) epsilons() []sets.SetInt {
+	if len(n.E) == n.Q {
+		return n.E
+	}
+	e := make([]sets.SetInt, n.Q)
+	for i := range e {
+		e[i] = sets.NewSetInt()
+		if i < len(n.E) {
+			e[i].Merge(n.E[i])
+		}
+	}
+	return e
+}
+
 // advanceSet returns a new set of integers representing the
 // provided set of integers where all the elements have been
 // increased in value by n. This is necessary for joining two
@@ -91,18 +127,29 @@ func advanceSet(s sets.SetInt, n int) sets.SetInt {
 	return newSet
 }
 
-// advanceD modifies in place a transition function to increase
-// in value by n all the advanced-to states, and returns the
-// modified transition function as a convenience. This is necessary
-// for joining two Nfas together whose states are both initially
-// assumed to be 0...Q-1.
+// advanceD returns a copy of a transition function in which all
+// the advanced-to states have been increased in value by n. This is
+// necessary for joining two Nfas together whose states are both
+// initially assumed to be 0...Q-1. The provided transition function
+// is not modified, and with n equal to zero, advanceD simply returns
+// a copy of it.
 func advanceD(d []map[rune]sets.SetInt, n int) []map[rune]sets.SetInt {
+	newD := make([]map[rune]sets.SetInt, len(d))
 	for i := range d {
+		newD[i] = make(map[rune]sets.SetInt)
 		for key, value := range d[i] {
-			if !value.IsEmpty() {
-				d[i][key] = advanceSet(value, n)
-			}
+			newD[i][key] = advanceSet(value, n)
 		}
 	}
-	return d
+	return newD
+}
+
+// advanceE returns a copy of a list of e-transitions, advanced in
+// the same way as by advanceD.
+func advanceE(e []sets.SetInt, n int) []sets.SetInt {
+	newE := make([]sets.SetInt, len(e))
+	for i := range e {
+		newE[i] = advanceSet(e[i], n)
+	}
+	return newE
 }
@@ -1,7 +1,9 @@
 package nfa_test
 
 import (
+	"encoding/json"
 	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/gods/sets"
 	"testing"
 )
 
@@ -272,3 +274,54 @@ func TestNfaAll1(t *testing.T) {
 		}
 	}
 }
+
+func TestNfaCombinatorsDontModifyInputs(t *testing.T) {
+	newX := func() nfa.Nfa {
+		return nfa.NewConcatNfa(
+			nfa.NewUnionNfa(nfa.NewRuneNfa('a'), nfa.NewRuneNfa('b')),
+			nfa.NewClosureNfa(nfa.NewRuneNfa('c')),
+		)
+	}
+
+	testCases := []struct {
+		combine func(x nfa.Nfa) nfa.Nfa
+		input   string
+		result  bool
+	}{
+		{func(x nfa.Nfa) nfa.Nfa { return nfa.NewConcatNfa(x, x) }, "acbcc", true},
+		{func(x nfa.Nfa) nfa.Nfa { return nfa.NewConcatNfa(x, x) }, "ac", false},
+		{func(x nfa.Nfa) nfa.Nfa { return nfa.NewUnionNfa(x, x) }, "bcc", true},
+		{func(x nfa.Nfa) nfa.Nfa { return nfa.NewUnionNfa(x, x) }, "ab", false},
+		{func(x nfa.Nfa) nfa.Nfa { return nfa.NewClosureNfa(x) }, "abcac", true},
+		{func(x nfa.Nfa) nfa.Nfa { return nfa.NewClosureNfa(x) }, "cab", false},
+		{func(x nfa.Nfa) nfa.Nfa {
+			return nfa.NewUnionNfa(nfa.NewConcatNfa(x, x), nfa.NewClosureNfa(x))
+		}, "acbab", true},
+	}
+
+	for n, tc := range testCases {
+		x := newX()
+		before, _ := json.Marshal(x)
+		c := tc.combine(x)
+		after, _ := json.Marshal(x)
+
+		if string(before) != string(after) {
+			t.Errorf("case %d, input Nfa modified, got %s, want %s",
+				n+1, after, before)
+		}
+		if !x.Accepts("ac") || x.Accepts("abc") {
+			t.Errorf("case %d, input Nfa no longer recognizes (a|b)c*", n+1)
+		}
+		if r := c.Accepts(tc.input); r != tc.result {
+			t.Errorf("case %d, input %q, got %v, want %v",
+				n+1, tc.input, r, tc.result)
+		}
+
+		// The result must not share state with its inputs.
+		c.D[0]['z'] = sets.NewSetInt(0)
+		c.E[len(c.E)-1].Insert(0)
+		if after, _ := json.Marshal(x); string(before) != string(after) {
+			t.Errorf("case %d, modifying result modified input Nfa", n+1)
+		}
+	}
+}
@@ -3,17 +3,19 @@ package nfa
 import (
 	"github.com/paulgriffiths/automata/dfa"
 	"github.com/paulgriffiths/gods/sets"
+	"sort"
 )
 
 // makeDtran builds a transition table that can be used to
-// build a deterministic finite automaton.
-func (n Nfa) makeDtran() dtran {
-	ds := newDtran(n.EclosureS(n.Qs))
+// build a deterministic finite automaton, considering only
+// transitions on the symbols of the alphabet.
+func (m Machine[T]) makeDtran() dtran[T] {
+	ds := newDtran[T](m.EclosureS(m.Qs))
 
 	i := 0
 	for i < ds.length() {
-		for _, letter := range n.S.Elements() {
-			nextState := n.EclosureT(n.Move(ds[i].nfaState, letter))
+		for _, letter := range m.S {
+			nextState := m.EclosureT(m.Move(ds[i].nfaState, letter))
 			if j, yes := ds.stateExists(nextState); yes {
 				ds.addTrans(i, j, letter)
 			} else {
@@ -30,17 +32,70 @@ func (n Nfa) makeDtran() dtran {
 // ToDfa converts a nondeterministic finite automaton to a
 // deterministic finite automaton.
 func (n Nfa) ToDfa() dfa.Dfa {
-	ds := n.makeDtran()
+	d, _ := n.ToDfaStates()
+	return d
+}
+
+// ToDfaStates converts a nondeterministic finite automaton to a
+// deterministic finite automaton in the same way as ToDfa, and also
+// returns, for each state of the deterministic finite automaton,
+// the set of states of the nondeterministic finite automaton which
+// it represents.
+func (n Nfa) ToDfaStates() (dfa.Dfa, []sets.SetInt) {
+	alphabet := n.S.Elements()
+	sort.Slice(alphabet, func(i, j int) bool {
+		return alphabet[i] < alphabet[j]
+	})
+
+	m, states := n.machine(alphabet).toDfaStates()
+	d := dfa.Dfa{Q: m.Q, S: n.S, D: m.D, Qs: m.Qs, F: m.F}
+	return d, states
+}
+
+// toDfaStates converts the Machine to a deterministic Machine as
+// described for Nfa.ToDfaStates, considering only transitions on the
+// symbols of its alphabet.
+func (m Machine[T]) toDfaStates() (dfa.Machine[T], []sets.SetInt) {
+	ds := m.makeDtran()
 
 	accepts := sets.NewSetInt()
-	tfunc := []map[rune]int{}
+	tfunc := []map[T]int{}
+	states := []sets.SetInt{}
 
 	for i := 0; i < ds.length(); i++ {
-		if !n.F.Intersection(ds[i].nfaState).IsEmpty() {
+		if !m.F.Intersection(ds[i].nfaState).IsEmpty() {
 			accepts.Insert(i)
 		}
 		tfunc = append(tfunc, ds[i].trans)
+		states = append(states, ds[i].nfaState)
 	}
 
-	return dfa.Dfa{ds.length(), n.S, tfunc, 0, accepts}
+	d := dfa.Machine[T]{
+		Q:  ds.length(),
+		S:  append([]T{}, m.S...),
+		D:  tfunc,
+		Qs: 0,
+		F:  accepts,
+	}
+	return d, states
+}
+
+// ToTaggedDfa converts a nondeterministic finite automaton to a
+// deterministic finite automaton in the same way as ToDfa, labeling
+// each state of the result with the union of the provided tags of
+// the states of the nondeterministic finite automaton which it
+// represents. The tags are indexed by state, and may have fewer
+// entries than there are states.
+func (n Nfa) ToTaggedDfa(tags []sets.SetInt) dfa.TaggedDfa {
+	d, states := n.ToDfaStates()
+	result := dfa.TaggedDfa{Dfa: d, T: make([]sets.SetInt, d.Q)}
+	for i, state := range states {
+		result.T[i] = sets.NewSetInt()
+		for _, q := range state.Elements() {
+			if q < len(tags) {
+				result.T[i].Merge(tags[q])
+			}
+		}
+	}
+	return result
 }
@@ -0,0 +1,27 @@
+package nfa
+
+import "github.com/paulgriffiths/automata/internal/gnfa"
+
+// ToRegex returns a regular expression matching exactly the strings
+// accepted by the Nfa, computed by the state elimination algorithm,
+// and true. As for dfa.Dfa.ToRegex, it returns the empty string and
+// false if the language of the Nfa cannot be written in the syntax
+// accepted by package regex. The alphabet used is that of the Nfa
+// together with any other runes on which it has transitions.
+func (n Nfa) ToRegex() (string, bool) {
+	alphabet := n.alphabet()
+	g := gnfa.New(n.Q, n.Qs, n.F.Elements())
+	for from, trans := range n.D {
+		for _, letter := range alphabet {
+			for _, to := range trans[letter].Elements() {
+				g.AddRune(from, to, letter)
+			}
+		}
+	}
+	for from, states := range n.E {
+		for _, to := range states.Elements() {
+			g.AddEpsilon(from, to)
+		}
+	}
+	return gnfa.String(g.Regex())
+}
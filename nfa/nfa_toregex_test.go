@@ -0,0 +1,94 @@
+package nfa_test
+
+import (
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/automata/regex"
+	"github.com/paulgriffiths/gods/sets"
+	"testing"
+)
+
+func TestNfaToRegex(t *testing.T) {
+	testCases := []nfa.Nfa{
+		nfa.NewUnionNfa(nfa.NewRuneNfa('a'), nfa.NewRuneNfa('b')),
+		nfa.NewClosureNfa(nfa.NewConcatNfa(nfa.NewRuneNfa('a'),
+			nfa.NewRuneNfa('b'))),
+		// Recognizes aa*|bb*.
+		// Compilers, figure 3.26.
+		{
+			5,
+			sets.NewSetRune('a', 'b'),
+			[]map[rune]sets.SetInt{
+				{},
+				{'a': sets.NewSetInt(2)},
+				{'a': sets.NewSetInt(2)},
+				{'b': sets.NewSetInt(4)},
+				{'b': sets.NewSetInt(4)},
+			},
+			[]sets.SetInt{
+				sets.NewSetInt(1, 3),
+				sets.NewSetInt(),
+				sets.NewSetInt(),
+				sets.NewSetInt(),
+				sets.NewSetInt(),
+			},
+			0,
+			sets.NewSetInt(2, 4),
+		},
+	}
+
+	for n, tc := range testCases {
+		s, ok := tc.ToRegex()
+		if !ok {
+			t.Errorf("case %d, couldn't convert", n+1)
+			continue
+		}
+		r := regex.Compile(s)
+		if r == nil {
+			t.Errorf("case %d, couldn't compile %q", n+1, s)
+			continue
+		}
+		for _, input := range allStrings("ab", 6) {
+			if got, want := r.Match(input), tc.Accepts(input); got != want {
+				t.Errorf("case %d, %q, input %q, got %t, want %t",
+					n+1, s, input, got, want)
+			}
+		}
+	}
+}
+
+func TestNfaToRegexInexpressible(t *testing.T) {
+	testCases := []nfa.Nfa{
+		// The empty language.
+		{1, sets.NewSetRune('a'), []map[rune]sets.SetInt{{}}, nil, 0,
+			sets.NewSetInt()},
+		// The language containing only the empty string.
+		{1, sets.NewSetRune('a'), []map[rune]sets.SetInt{{}}, nil, 0,
+			sets.NewSetInt(0)},
+		// The empty string or a.
+		nfa.NewUnionNfa(nfa.NewRuneNfa('a'), nfa.Nfa{
+			1, sets.NewSetRune(), []map[rune]sets.SetInt{{}}, nil, 0,
+			sets.NewSetInt(0)}),
+		// A rune which is not a letter or a digit.
+		nfa.NewRuneNfa('('),
+	}
+
+	for n, tc := range testCases {
+		if got, ok := tc.ToRegex(); ok || got != "" {
+			t.Errorf("case %d, got %q, %t, want %q, false", n+1, got, ok, "")
+		}
+	}
+}
+
+func TestNfaToRegexAlphabet(t *testing.T) {
+	// The transition on b is outside the alphabet.
+	n := nfa.Nfa{
+		Q:  2,
+		S:  sets.NewSetRune('a'),
+		D:  []map[rune]sets.SetInt{{'a': sets.NewSetInt(1), 'b': sets.NewSetInt(1)}, {}},
+		Qs: 0,
+		F:  sets.NewSetInt(1),
+	}
+	if got, ok := n.ToRegex(); !ok || got != "a|b" {
+		t.Errorf("got %q, %t, want %q, true", got, ok, "a|b")
+	}
+}
@@ -0,0 +1,83 @@
+package nfa
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ValidationError describes the structural problems found in an Nfa
+// by Validate.
+type ValidationError struct {
+	Problems []string // Descriptions of each problem found
+}
+
+// Error returns a description of every problem found.
+func (e *ValidationError) Error() string {
+	return "nfa: invalid automaton: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks that the Nfa is well-formed, that is, that it has
+// a transition map for each of its states and no more e-transition
+// sets than states, that its start state, accepting states and the
+// targets of its transitions and e-transitions are all states of the
+// Nfa, and that every rune with a transition is in its alphabet. If
+// it is not, the returned error is a *ValidationError describing
+// every problem found, otherwise it is nil.
+func (n Nfa) Validate() error {
+	problems := []string{}
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if n.Q < 0 {
+		addProblem("negative number of states %d", n.Q)
+	}
+	if len(n.D) != n.Q {
+		addProblem("got %d transition maps, want %d", len(n.D), n.Q)
+	}
+	if len(n.E) > n.Q {
+		addProblem("got %d e-transition sets, want at most %d", len(n.E), n.Q)
+	}
+	if n.Qs < 0 || n.Qs >= n.Q {
+		addProblem("start state %d out of range", n.Qs)
+	}
+	for _, q := range n.F.Elements() {
+		if q < 0 || q >= n.Q {
+			addProblem("accepting state %d out of range", q)
+		}
+	}
+
+	for q, trans := range n.D {
+		letters := []rune{}
+		for letter := range trans {
+			letters = append(letters, letter)
+		}
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+
+		for _, letter := range letters {
+			if !n.S.Contains(letter) {
+				addProblem("state %d, rune %q: rune not in alphabet", q, letter)
+			}
+			for _, to := range trans[letter].Elements() {
+				if to < 0 || to >= n.Q {
+					addProblem("state %d, rune %q: target state %d out of range",
+						q, letter, to)
+				}
+			}
+		}
+	}
+
+	for q, states := range n.E {
+		for _, to := range states.Elements() {
+			if to < 0 || to >= n.Q {
+				addProblem("state %d, ε: target state %d out of range", q, to)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{problems}
+	}
+	return nil
+}
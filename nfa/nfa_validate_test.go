@@ -0,0 +1,86 @@
+package nfa_test
+
+import (
+	"encoding/json"
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/gods/sets"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		n        nfa.Nfa
+		problems []string
+	}{
+		{nfa3, nil},
+		{nfa.NewRuneNfa('a'), nil},
+		{
+			nfa.Nfa{
+				Q: 2,
+				S: sets.NewSetRune('a'),
+				D: []map[rune]sets.SetInt{
+					{'a': sets.NewSetInt(1, 2), 'b': sets.NewSetInt(0)},
+					{},
+				},
+				E:  []sets.SetInt{sets.NewSetInt(), sets.NewSetInt(5)},
+				Qs: -1,
+				F:  sets.NewSetInt(2),
+			},
+			[]string{
+				"start state -1 out of range",
+				"accepting state 2 out of range",
+				"state 0, rune 'a': target state 2 out of range",
+				"state 0, rune 'b': rune not in alphabet",
+				"state 1, ε: target state 5 out of range",
+			},
+		},
+		{
+			nfa.Nfa{
+				Q:  1,
+				S:  sets.NewSetRune(),
+				D:  []map[rune]sets.SetInt{{}, {}},
+				E:  []sets.SetInt{nil, nil},
+				Qs: 0,
+				F:  sets.NewSetInt(),
+			},
+			[]string{
+				"got 2 transition maps, want 1",
+				"got 2 e-transition sets, want at most 1",
+			},
+		},
+	}
+
+	for n, tc := range testCases {
+		err := tc.n.Validate()
+		if tc.problems == nil {
+			if err != nil {
+				t.Errorf("case %d, got %v, want nil", n+1, err)
+			}
+			continue
+		}
+
+		verr, ok := err.(*nfa.ValidationError)
+		if !ok {
+			t.Errorf("case %d, got %v, want *ValidationError", n+1, err)
+			continue
+		}
+		if !reflect.DeepEqual(verr.Problems, tc.problems) {
+			t.Errorf("case %d, got %q, want %q", n+1, verr.Problems, tc.problems)
+		}
+	}
+}
+
+func TestUnmarshalValidates(t *testing.T) {
+	var n nfa.Nfa
+	err := json.Unmarshal([]byte(`{"states":1,"alphabet":["a"],"start":0,`+
+		`"accepting":[],"transitions":[{"a":[0]}],"epsilons":[[3]]}`), &n)
+	if _, ok := err.(*nfa.ValidationError); !ok {
+		t.Errorf("got %v, want *ValidationError", err)
+	}
+
+	err = n.UnmarshalText([]byte("nfa\n     'a'\n> 0  {0,1}\n"))
+	if _, ok := err.(*nfa.ValidationError); !ok {
+		t.Errorf("got %v, want *ValidationError", err)
+	}
+}
@@ -0,0 +1,4 @@
+/*
+Package pda implements nondeterministic pushdown automata.
+*/
+package pda
@@ -0,0 +1,44 @@
+package pda_test
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/automata/pda"
+	"github.com/paulgriffiths/gods/sets"
+)
+
+func Example() {
+	automaton := pda.Pda{
+		Q: 2,
+		S: sets.NewSetRune('(', ')'),
+		G: sets.NewSetRune('Z', '('),
+		D: [][]pda.Transition{
+			{
+				{In: '(', Pop: pda.Epsilon, Push: "(", To: 0},
+				{In: ')', Pop: '(', Push: "", To: 0},
+				{In: pda.Epsilon, Pop: 'Z', Push: "Z", To: 1},
+			},
+			{},
+		},
+		Qs:     0,
+		Z:      'Z',
+		F:      sets.NewSetInt(1),
+		Accept: pda.FinalState,
+	}
+
+	for _, s := range []string{"(()())", "(()", "())("} {
+		if automaton.Accepts(s) {
+			fmt.Printf("PDA accepts string %q.\n", s)
+		} else {
+			fmt.Printf("PDA does not accept string %q.\n", s)
+		}
+	}
+
+	empty := automaton.ToEmptyStack()
+	fmt.Println(empty.Accept == pda.EmptyStack, empty.Accepts("(()())"))
+
+	// Output:
+	// PDA accepts string "(()())".
+	// PDA does not accept string "(()".
+	// PDA does not accept string "())(".
+	// true true
+}
@@ -0,0 +1,146 @@
+package pda
+
+import (
+	"github.com/paulgriffiths/gods/sets"
+	"unicode/utf8"
+)
+
+// Epsilon is the input rune of a transition which reads no input, and
+// the stack symbol of a transition which pops nothing.
+const Epsilon rune = -1
+
+// Acceptance selects how a pushdown automaton accepts its input.
+type Acceptance int
+
+// Acceptance modes.
+const (
+	// FinalState accepts if the automaton can read all of its input
+	// and stop in an accepting state.
+	FinalState Acceptance = iota
+
+	// EmptyStack accepts if the automaton can read all of its input
+	// and stop with an empty stack, in any state.
+	EmptyStack
+)
+
+// Transition is a transition of a pushdown automaton.
+type Transition struct {
+	In   rune   // Input rune read, or Epsilon
+	Pop  rune   // Stack symbol popped, or Epsilon
+	Push string // Stack symbols pushed, the first ending on top
+	To   int    // Next state
+}
+
+// Pda implements a nondeterministic pushdown automaton.
+type Pda struct {
+	Q      int            // Number of states
+	S      sets.SetRune   // Input alphabet
+	G      sets.SetRune   // Stack alphabet
+	D      [][]Transition // Transitions from each state
+	Qs     int            // Start state
+	Z      rune           // Initial stack symbol, or Epsilon if none
+	F      sets.SetInt    // Set of accepting states
+	Accept Acceptance     // Acceptance mode
+}
+
+// config is a configuration of a pushdown automaton: its state, the
+// number of bytes of input read, and its stack, with the top of the
+// stack last.
+type config struct {
+	q     int
+	pos   int
+	stack string
+}
+
+// AcceptsWithin returns true if the Pda accepts the provided string,
+// according to its acceptance mode, without its stack ever holding
+// more than maxStack symbols. The configurations of the Pda are
+// searched breadth-first from its start state with its initial stack
+// symbol, and each configuration is visited only once, so the search
+// always terminates. A transition which pops Epsilon may be taken
+// whatever is on the stack, even if it is empty, while one which pops
+// a symbol may only be taken if that symbol is on top of the stack.
+func (p Pda) AcceptsWithin(input string, maxStack int) bool {
+	start := config{p.Qs, 0, ""}
+	if p.Z != Epsilon {
+		start.stack = string(p.Z)
+	}
+	if utf8.RuneCountInString(start.stack) > maxStack {
+		return false
+	}
+
+	seen := map[config]bool{start: true}
+	queue := []config{start}
+
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+
+		if c.pos == len(input) && p.accepting(c) {
+			return true
+		}
+
+		for _, tr := range p.D[c.q] {
+			next := c
+			next.q = tr.To
+
+			if tr.In != Epsilon {
+				if c.pos == len(input) {
+					continue
+				}
+				letter, size := utf8.DecodeRuneInString(input[c.pos:])
+				if letter != tr.In {
+					continue
+				}
+				next.pos += size
+			}
+
+			if tr.Pop != Epsilon {
+				top, ok := topOf(c.stack)
+				if !ok || top != tr.Pop {
+					continue
+				}
+				next.stack = c.stack[:len(c.stack)-len(string(top))]
+			}
+			next.stack += reverse(tr.Push)
+
+			if utf8.RuneCountInString(next.stack) > maxStack || seen[next] {
+				continue
+			}
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+
+	return false
+}
+
+// accepting returns true if the configuration, having read all of
+// the input, is accepting.
+func (p Pda) accepting(c config) bool {
+	if p.Accept == EmptyStack {
+		return c.stack == ""
+	}
+	return p.F.Contains(c.q)
+}
+
+// topOf returns the symbol on top of the stack, and false if the
+// stack is empty.
+func topOf(stack string) (rune, bool) {
+	if stack == "" {
+		return 0, false
+	}
+	r, _ := utf8.DecodeLastRuneInString(stack)
+	return r, true
+}
+
+// reverse returns the runes of s in reverse order, so that a string
+// of symbols to push, with the first ending on top, can be appended
+// to a stack with its top last.
+func reverse(s string) string {
+	runes := []rune(s)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
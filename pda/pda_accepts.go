@@ -0,0 +1,248 @@
+package pda
+
+// Accepts returns true if the Pda accepts the provided string,
+// according to its acceptance mode, treating transitions which pop
+// Epsilon as described for AcceptsWithin. Unlike AcceptsWithin, it
+// places no bound on the size of the stack. It parses the input with
+// Earley's algorithm, using the context-free grammar constructed
+// from a pushdown automaton, whose symbols are the triples
+// (p, X, q) such that the automaton can go from state p with X on
+// top of its stack to state q, removing X without touching the rest
+// of the stack. Each way for the Pda to start removing a symbol at a
+// given position is explored only once, so the search always
+// terminates, in time at most proportional to the cube of the length
+// of the input, and much less for most automata.
+func (p Pda) Accepts(input string) bool {
+	e := newEarley(p.normalize(), []rune(input))
+	first := call{e.a.start, e.a.bottom, 0}
+	e.predict(first)
+
+	for j := range e.agenda {
+		for i := 0; i < len(e.agenda[j]); i++ {
+			it := e.agenda[j][i]
+			mv := e.a.moves[it.move]
+
+			if it.popped == len(mv.push) {
+				c := call{mv.from, mv.pop, it.origin}
+				if c == first && j == len(e.input) {
+					return true
+				}
+				e.complete(c, it.state, j)
+				continue
+			}
+
+			c := call{it.state, mv.push[it.popped], j}
+			if _, ok := e.waiting[c]; !ok {
+				e.waiting[c] = []item{}
+				e.predict(c)
+			}
+			e.waiting[c] = append(e.waiting[c], it)
+			for _, q := range e.completed[c] {
+				e.add(it.advance(q), j)
+			}
+		}
+	}
+
+	return false
+}
+
+// call is the removal of a stack symbol by an automaton, starting in
+// the provided state at the provided position in the input.
+type call struct {
+	state  int
+	symbol int
+	pos    int
+}
+
+// item is a move being taken by an automaton, which it took at
+// position origin, having since removed the first popped of the
+// symbols it pushed, and reached the provided state.
+type item struct {
+	move   int
+	popped int
+	origin int
+	state  int
+}
+
+// advance returns the item after removing another pushed symbol and
+// reaching state q.
+func (it item) advance(q int) item {
+	it.popped++
+	it.state = q
+	return it
+}
+
+// earley holds the state of Earley's algorithm for a normalized
+// automaton and an input.
+type earley struct {
+	a      normalized
+	input  []rune
+	byTop  map[[2]int][]int // Moves from each state popping each symbol
+	agenda [][]item         // Items at each position, in order found
+	seen   []map[item]bool  // Items at each position
+
+	// waiting holds the items waiting for each call to complete, and
+	// completed the states in which the call has completed so far,
+	// all at the position of the call, since a call at one position
+	// is waited for only at that position.
+	waiting   map[call][]item
+	completed map[call][]int
+	done      map[call]map[int]bool
+}
+
+func newEarley(a normalized, input []rune) *earley {
+	e := &earley{
+		a:         a,
+		input:     input,
+		byTop:     make(map[[2]int][]int),
+		agenda:    make([][]item, len(input)+1),
+		seen:      make([]map[item]bool, len(input)+1),
+		waiting:   make(map[call][]item),
+		completed: make(map[call][]int),
+		done:      make(map[call]map[int]bool),
+	}
+	for k, mv := range a.moves {
+		top := [2]int{mv.from, mv.pop}
+		e.byTop[top] = append(e.byTop[top], k)
+	}
+	for j := range e.seen {
+		e.seen[j] = make(map[item]bool)
+	}
+	return e
+}
+
+// add adds an item at position j, if it is new.
+func (e *earley) add(it item, j int) {
+	if !e.seen[j][it] {
+		e.seen[j][it] = true
+		e.agenda[j] = append(e.agenda[j], it)
+	}
+}
+
+// predict adds an item for each move which can start the call.
+func (e *earley) predict(c call) {
+	for _, k := range e.byTop[[2]int{c.state, c.symbol}] {
+		mv := e.a.moves[k]
+		it := item{move: k, origin: c.pos, state: mv.to}
+		if mv.in == Epsilon {
+			e.add(it, c.pos)
+		} else if c.pos < len(e.input) && e.input[c.pos] == mv.in {
+			e.add(it, c.pos+1)
+		}
+	}
+}
+
+// complete records that the call can complete in state q at position
+// j, and advances the items waiting for it.
+func (e *earley) complete(c call, q, j int) {
+	if j == c.pos {
+		if e.done[c] == nil {
+			e.done[c] = make(map[int]bool)
+		}
+		if e.done[c][q] {
+			return
+		}
+		e.done[c][q] = true
+		e.completed[c] = append(e.completed[c], q)
+	}
+	for _, w := range e.waiting[c] {
+		e.add(w.advance(q), j)
+	}
+}
+
+// move is a transition of a normalized automaton. Stack symbols are
+// numbered, and every move pops exactly one.
+type move struct {
+	from int
+	in   rune
+	pop  int
+	push []int // Stack symbols pushed, the first ending on top
+	to   int
+}
+
+// normalized is an automaton which accepts the same strings as a Pda
+// but accepts by empty stack, starts with only the symbol bottom on
+// its stack, and has only moves which pop a symbol, so that it can do
+// nothing once its stack is empty.
+type normalized struct {
+	moves  []move
+	states int
+	start  int
+	bottom int
+}
+
+// normalize returns a normalized automaton accepting the same strings
+// as the Pda. A new bottom-of-stack symbol stands in for the Pda's
+// empty stack, and is placed beneath the initial stack symbol by a
+// new start state. Each transition which pops Epsilon is replaced by
+// one for each stack symbol which pops the symbol and pushes it back.
+// If the Pda accepts by empty stack, any state may pop the bottom
+// symbol and move to a new state with no moves. If it accepts by
+// final state, each accepting state may move to a new state which
+// pops every symbol.
+func (p Pda) normalize() normalized {
+	index := make(map[rune]int)
+	number := func(symbol rune) int {
+		if _, ok := index[symbol]; !ok {
+			index[symbol] = len(index)
+		}
+		return index[symbol]
+	}
+	numbers := func(symbols string) []int {
+		result := []int{}
+		for _, symbol := range symbols {
+			result = append(result, number(symbol))
+		}
+		return result
+	}
+
+	for _, symbol := range p.G.Elements() {
+		number(symbol)
+	}
+	for _, trans := range p.D {
+		for _, tr := range trans {
+			numbers(tr.Push)
+			if tr.Pop != Epsilon {
+				number(tr.Pop)
+			}
+		}
+	}
+	initial := []int{}
+	if p.Z != Epsilon {
+		initial = append(initial, number(p.Z))
+	}
+
+	a := normalized{states: p.Q + 2, start: p.Q, bottom: len(index)}
+	symbols := len(index) + 1
+	last := p.Q + 1
+
+	a.moves = append(a.moves,
+		move{a.start, Epsilon, a.bottom, append(initial, a.bottom), p.Qs})
+	for q, trans := range p.D {
+		for _, tr := range trans {
+			push := numbers(tr.Push)
+			if tr.Pop != Epsilon {
+				a.moves = append(a.moves, move{q, tr.In, index[tr.Pop], push, tr.To})
+				continue
+			}
+			for symbol := 0; symbol < symbols; symbol++ {
+				a.moves = append(a.moves,
+					move{q, tr.In, symbol, append(append([]int{}, push...), symbol), tr.To})
+			}
+		}
+	}
+
+	if p.Accept == EmptyStack {
+		for q := 0; q < p.Q; q++ {
+			a.moves = append(a.moves, move{q, Epsilon, a.bottom, nil, last})
+		}
+		return a
+	}
+	for symbol := 0; symbol < symbols; symbol++ {
+		for _, q := range p.F.Elements() {
+			a.moves = append(a.moves, move{q, Epsilon, symbol, nil, last})
+		}
+		a.moves = append(a.moves, move{last, Epsilon, symbol, nil, last})
+	}
+	return a
+}
@@ -0,0 +1,250 @@
+package pda_test
+
+import (
+	"github.com/paulgriffiths/automata/pda"
+	"github.com/paulgriffiths/gods/sets"
+	"strings"
+	"testing"
+)
+
+// brackets accepts balanced strings of round and square brackets, by
+// final state.
+var brackets = pda.Pda{
+	Q: 2,
+	S: sets.NewSetRune('(', ')', '[', ']'),
+	G: sets.NewSetRune('Z', '(', '['),
+	D: [][]pda.Transition{
+		{
+			{In: '(', Pop: pda.Epsilon, Push: "(", To: 0},
+			{In: '[', Pop: pda.Epsilon, Push: "[", To: 0},
+			{In: ')', Pop: '(', Push: "", To: 0},
+			{In: ']', Pop: '[', Push: "", To: 0},
+			{In: pda.Epsilon, Pop: 'Z', Push: "Z", To: 1},
+		},
+		{},
+	},
+	Qs:     0,
+	Z:      'Z',
+	F:      sets.NewSetInt(1),
+	Accept: pda.FinalState,
+}
+
+// anbn accepts a^n b^n, by empty stack.
+var anbn = pda.Pda{
+	Q: 2,
+	S: sets.NewSetRune('a', 'b'),
+	G: sets.NewSetRune('A'),
+	D: [][]pda.Transition{
+		{
+			{In: 'a', Pop: pda.Epsilon, Push: "A", To: 0},
+			{In: 'b', Pop: 'A', Push: "", To: 1},
+		},
+		{
+			{In: 'b', Pop: 'A', Push: "", To: 1},
+		},
+	},
+	Qs:     0,
+	Z:      pda.Epsilon,
+	F:      sets.NewSetInt(),
+	Accept: pda.EmptyStack,
+}
+
+// palindromes accepts even-length palindromes over a and b, by empty
+// stack, guessing where the middle is.
+var palindromes = pda.Pda{
+	Q: 2,
+	S: sets.NewSetRune('a', 'b'),
+	G: sets.NewSetRune('a', 'b'),
+	D: [][]pda.Transition{
+		{
+			{In: 'a', Pop: pda.Epsilon, Push: "a", To: 0},
+			{In: 'b', Pop: pda.Epsilon, Push: "b", To: 0},
+			{In: pda.Epsilon, Pop: pda.Epsilon, Push: "", To: 1},
+		},
+		{
+			{In: 'a', Pop: 'a', Push: "", To: 1},
+			{In: 'b', Pop: 'b', Push: "", To: 1},
+		},
+	},
+	Qs:     0,
+	Z:      pda.Epsilon,
+	F:      sets.NewSetInt(),
+	Accept: pda.EmptyStack,
+}
+
+// allStrings returns all strings over the provided alphabet with
+// lengths up to and including n.
+func allStrings(alphabet string, n int) []string {
+	result := []string{""}
+	last := []string{""}
+	for i := 0; i < n; i++ {
+		next := []string{}
+		for _, s := range last {
+			for _, r := range alphabet {
+				next = append(next, s+string(r))
+			}
+		}
+		result = append(result, next...)
+		last = next
+	}
+	return result
+}
+
+// balanced returns true if the brackets in s are balanced.
+func balanced(s string) bool {
+	stack := []rune{}
+	pairs := map[rune]rune{')': '(', ']': '['}
+	for _, r := range s {
+		if open, ok := pairs[r]; ok {
+			if len(stack) == 0 || stack[len(stack)-1] != open {
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		} else {
+			stack = append(stack, r)
+		}
+	}
+	return len(stack) == 0
+}
+
+// languages pairs each of the automata above with the alphabet of
+// its language and a function reporting whether a string is in it.
+var languages = []struct {
+	name     string
+	p        pda.Pda
+	alphabet string
+	want     func(string) bool
+}{
+	{"brackets", brackets, "()[]", balanced},
+	{"anbn", anbn, "ab", func(s string) bool {
+		n := len(s) / 2
+		return s == strings.Repeat("a", n)+strings.Repeat("b", n)
+	}},
+	{"palindromes", palindromes, "ab", func(s string) bool {
+		for i := 0; i < len(s)/2; i++ {
+			if s[i] != s[len(s)-1-i] {
+				return false
+			}
+		}
+		return len(s)%2 == 0
+	}},
+}
+
+func TestAccepts(t *testing.T) {
+	for _, l := range languages {
+		if err := l.p.Validate(); err != nil {
+			t.Errorf("%s: invalid automaton: %v", l.name, err)
+		}
+		for _, s := range allStrings(l.alphabet, 6) {
+			if got, want := l.p.Accepts(s), l.want(s); got != want {
+				t.Errorf("%s: string %q, got %t, want %t", l.name, s, got, want)
+			}
+		}
+	}
+}
+
+func TestAcceptsDeepNesting(t *testing.T) {
+	s := strings.Repeat("([", 500) + strings.Repeat("])", 500)
+	if !brackets.Accepts(s) {
+		t.Errorf("deeply nested string not accepted")
+	}
+	if brackets.Accepts(s + ")") {
+		t.Errorf("unbalanced string accepted")
+	}
+}
+
+func TestAcceptsUnboundedGrowth(t *testing.T) {
+	// climb grows its stack to ten symbols, A to J, without reading
+	// any input, before it can read an a and then empty its stack.
+	climb := pda.Pda{
+		Q:      2,
+		S:      sets.NewSetRune('a'),
+		G:      sets.NewSetRune([]rune("ABCDEFGHIJ")...),
+		D:      make([][]pda.Transition, 2),
+		Qs:     0,
+		Z:      'A',
+		F:      sets.NewSetInt(),
+		Accept: pda.EmptyStack,
+	}
+	for symbol := 'A'; symbol <= 'J'; symbol++ {
+		if symbol < 'J' {
+			climb.D[0] = append(climb.D[0],
+				pda.Transition{In: pda.Epsilon, Pop: symbol, Push: string(symbol+1) + string(symbol), To: 0})
+		}
+		climb.D[1] = append(climb.D[1],
+			pda.Transition{In: pda.Epsilon, Pop: symbol, Push: "", To: 1})
+	}
+	climb.D[0] = append(climb.D[0], pda.Transition{In: 'a', Pop: 'J', Push: "", To: 1})
+
+	if err := climb.Validate(); err != nil {
+		t.Fatalf("invalid automaton: %v", err)
+	}
+	testCases := []struct {
+		input string
+		want  bool
+	}{
+		{"a", true},
+		{"", false},
+		{"aa", false},
+	}
+	for n, tc := range testCases {
+		if got := climb.Accepts(tc.input); got != tc.want {
+			t.Errorf("case %d, got %t, want %t", n+1, got, tc.want)
+		}
+		if got := climb.AcceptsWithin(tc.input, 10); got != tc.want {
+			t.Errorf("case %d, got %t within 10 symbols, want %t", n+1, got, tc.want)
+		}
+	}
+
+	climb.Accept = pda.FinalState
+	climb.F = sets.NewSetInt(1)
+	if !climb.Accepts("a") {
+		t.Errorf("string not accepted by final state")
+	}
+}
+
+func TestAcceptsWithin(t *testing.T) {
+	testCases := []struct {
+		input    string
+		maxStack int
+		want     bool
+	}{
+		{"(())", 3, true},
+		{"(())", 2, false},
+		{"((()))", 3, false},
+		{"", 1, true},
+		{"", 0, false},
+	}
+
+	for n, tc := range testCases {
+		if got := brackets.AcceptsWithin(tc.input, tc.maxStack); got != tc.want {
+			t.Errorf("case %d, got %t, want %t", n+1, got, tc.want)
+		}
+	}
+}
+
+func TestAcceptsTerminates(t *testing.T) {
+	// grow pushes forever without reading input, and never accepts.
+	grow := pda.Pda{
+		Q: 2,
+		S: sets.NewSetRune('a'),
+		G: sets.NewSetRune('A'),
+		D: [][]pda.Transition{
+			{
+				{In: pda.Epsilon, Pop: pda.Epsilon, Push: "AA", To: 0},
+				{In: 'a', Pop: 'A', Push: "", To: 1},
+			},
+			{},
+		},
+		Qs: 0,
+		Z:  pda.Epsilon,
+		F:  sets.NewSetInt(),
+	}
+	if grow.Accepts("a") {
+		t.Errorf("string accepted")
+	}
+	grow.Accept = pda.EmptyStack
+	if grow.Accepts("a") {
+		t.Errorf("string accepted by empty stack")
+	}
+}
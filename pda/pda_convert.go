@@ -0,0 +1,119 @@
+package pda
+
+import "github.com/paulgriffiths/gods/sets"
+
+// ToEmptyStack returns a Pda accepting by empty stack which accepts
+// the same strings as the Pda does by its own acceptance mode. If the
+// Pda already accepts by empty stack, a copy of it is returned.
+// Otherwise, the result has a new start state, which places a new
+// bottom-of-stack symbol beneath the initial stack symbol, so that
+// the stack only empties when the new symbol is popped, and a new
+// state reached from each accepting state, which pops every symbol.
+func (p Pda) ToEmptyStack() Pda {
+	result := p.copy()
+	if p.Accept == EmptyStack {
+		return result
+	}
+
+	bottom := p.freshSymbol()
+	start := result.addState()
+	drain := result.addState()
+
+	result.D[start] = append(result.D[start],
+		Transition{Epsilon, bottom, initialPush(p.Z, bottom), p.Qs})
+	for _, q := range p.F.Elements() {
+		result.D[q] = append(result.D[q], Transition{Epsilon, Epsilon, "", drain})
+	}
+	result.G.Insert(bottom)
+	for _, symbol := range result.G.Elements() {
+		result.D[drain] = append(result.D[drain],
+			Transition{Epsilon, symbol, "", drain})
+	}
+
+	result.Qs = start
+	result.Z = bottom
+	result.F = sets.NewSetInt()
+	result.Accept = EmptyStack
+	return result
+}
+
+// ToFinalState returns a Pda accepting by final state which accepts
+// the same strings as the Pda does by its own acceptance mode. If the
+// Pda already accepts by final state, a copy of it is returned.
+// Otherwise, the result has a new start state, which places a new
+// bottom-of-stack symbol beneath the initial stack symbol, and a new
+// accepting state, reached from every state by popping that symbol,
+// which is only on top of the stack when the Pda's stack is empty.
+func (p Pda) ToFinalState() Pda {
+	result := p.copy()
+	if p.Accept == FinalState {
+		return result
+	}
+
+	bottom := p.freshSymbol()
+	start := result.addState()
+	final := result.addState()
+
+	result.D[start] = append(result.D[start],
+		Transition{Epsilon, bottom, initialPush(p.Z, bottom), p.Qs})
+	for q := 0; q < p.Q; q++ {
+		result.D[q] = append(result.D[q], Transition{Epsilon, bottom, "", final})
+	}
+	result.G.Insert(bottom)
+
+	result.Qs = start
+	result.Z = bottom
+	result.F = sets.NewSetInt(final)
+	result.Accept = FinalState
+	return result
+}
+
+// initialPush returns the symbols a converted Pda pushes onto its new
+// bottom-of-stack symbol in place of the original initial stack
+// symbol.
+func initialPush(z, bottom rune) string {
+	if z == Epsilon {
+		return string(bottom)
+	}
+	return string(z) + string(bottom)
+}
+
+// freshSymbol returns a stack symbol which the Pda does not use,
+// preferring ⊥.
+func (p Pda) freshSymbol() rune {
+	used := sets.NewSetRune(p.G.Elements()...)
+	used.Insert(p.Z)
+	for _, trans := range p.D {
+		for _, tr := range trans {
+			used.Insert(tr.Pop)
+			used.Insert([]rune(tr.Push)...)
+		}
+	}
+
+	symbol := '⊥'
+	for used.Contains(symbol) {
+		symbol++
+	}
+	return symbol
+}
+
+// addState adds a new state with no transitions, and returns its
+// number.
+func (p *Pda) addState() int {
+	p.D = append(p.D, nil)
+	p.Q++
+	return p.Q - 1
+}
+
+// copy returns a deep copy of the Pda.
+func (p Pda) copy() Pda {
+	result := p
+	result.S = sets.NewSetRune(p.S.Elements()...)
+	result.G = sets.NewSetRune(p.G.Elements()...)
+	result.F = sets.NewSetInt(p.F.Elements()...)
+	result.D = make([][]Transition, len(p.D))
+	for q, trans := range p.D {
+		result.D[q] = append([]Transition{}, trans...)
+	}
+	return result
+}
@@ -0,0 +1,47 @@
+package pda_test
+
+import (
+	"github.com/paulgriffiths/automata/pda"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	for _, l := range languages {
+		empty := l.p.ToEmptyStack()
+		final := l.p.ToFinalState()
+		if empty.Accept != pda.EmptyStack || final.Accept != pda.FinalState {
+			t.Errorf("%s: got modes %d and %d", l.name, empty.Accept, final.Accept)
+		}
+
+		converted := []struct {
+			name string
+			p    pda.Pda
+		}{
+			{"empty stack", empty},
+			{"final state", final},
+			{"round trip", empty.ToFinalState()},
+			{"other round trip", final.ToEmptyStack()},
+		}
+		for _, c := range converted {
+			if err := c.p.Validate(); err != nil {
+				t.Errorf("%s, %s: invalid automaton: %v", l.name, c.name, err)
+			}
+			for _, s := range allStrings(l.alphabet, 5) {
+				if got, want := c.p.Accepts(s), l.want(s); got != want {
+					t.Errorf("%s, %s: string %q, got %t, want %t",
+						l.name, c.name, s, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestConvertDoesntModifyInput(t *testing.T) {
+	p := brackets
+	q, transitions := p.Q, len(p.D[0])
+	p.ToEmptyStack()
+	if p.Q != q || len(p.D) != q || len(p.D[0]) != transitions ||
+		p.G.Contains('⊥') || p.F.Length() != 1 {
+		t.Errorf("input automaton modified")
+	}
+}
@@ -0,0 +1,81 @@
+package pda
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes the structural problems found in a Pda
+// by Validate.
+type ValidationError struct {
+	Problems []string // Descriptions of each problem found
+}
+
+// Error returns a description of every problem found.
+func (e *ValidationError) Error() string {
+	return "pda: invalid automaton: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate checks that the Pda is well-formed, that is, that it has a
+// list of transitions for each of its states, that its start state,
+// accepting states and the targets of its transitions are all states
+// of the Pda, that every rune read is in its input alphabet, that its
+// initial stack symbol and every symbol popped or pushed is in its
+// stack alphabet, and that its acceptance mode is known. If it is
+// not, the returned error is a *ValidationError describing every
+// problem found, otherwise it is nil.
+func (p Pda) Validate() error {
+	problems := []string{}
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if p.Q < 0 {
+		addProblem("negative number of states %d", p.Q)
+	}
+	if len(p.D) != p.Q {
+		addProblem("got %d transition lists, want %d", len(p.D), p.Q)
+	}
+	if p.Qs < 0 || p.Qs >= p.Q {
+		addProblem("start state %d out of range", p.Qs)
+	}
+	if p.Z != Epsilon && !p.G.Contains(p.Z) {
+		addProblem("initial stack symbol %q not in stack alphabet", p.Z)
+	}
+	for _, q := range p.F.Elements() {
+		if q < 0 || q >= p.Q {
+			addProblem("accepting state %d out of range", q)
+		}
+	}
+	if p.Accept != FinalState && p.Accept != EmptyStack {
+		addProblem("unknown acceptance mode %d", p.Accept)
+	}
+
+	for q, trans := range p.D {
+		for i, tr := range trans {
+			if tr.In != Epsilon && !p.S.Contains(tr.In) {
+				addProblem("state %d, transition %d: rune %q not in alphabet",
+					q, i, tr.In)
+			}
+			if tr.Pop != Epsilon && !p.G.Contains(tr.Pop) {
+				addProblem("state %d, transition %d: popped symbol %q not in stack alphabet",
+					q, i, tr.Pop)
+			}
+			for _, symbol := range tr.Push {
+				if !p.G.Contains(symbol) {
+					addProblem("state %d, transition %d: pushed symbol %q not in stack alphabet",
+						q, i, symbol)
+				}
+			}
+			if tr.To < 0 || tr.To >= p.Q {
+				addProblem("state %d, transition %d: target state %d out of range",
+					q, i, tr.To)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{problems}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package pda_test
+
+import (
+	"github.com/paulgriffiths/automata/pda"
+	"github.com/paulgriffiths/gods/sets"
+	"reflect"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	p := pda.Pda{
+		Q: 2,
+		S: sets.NewSetRune('a'),
+		G: sets.NewSetRune('A'),
+		D: [][]pda.Transition{
+			{
+				{In: 'b', Pop: 'B', Push: "AC", To: 2},
+				{In: pda.Epsilon, Pop: pda.Epsilon, Push: "", To: 1},
+			},
+		},
+		Qs:     3,
+		Z:      'Z',
+		F:      sets.NewSetInt(5),
+		Accept: 7,
+	}
+
+	want := []string{
+		"got 1 transition lists, want 2",
+		"start state 3 out of range",
+		"initial stack symbol 'Z' not in stack alphabet",
+		"accepting state 5 out of range",
+		"unknown acceptance mode 7",
+		"state 0, transition 0: rune 'b' not in alphabet",
+		"state 0, transition 0: popped symbol 'B' not in stack alphabet",
+		"state 0, transition 0: pushed symbol 'C' not in stack alphabet",
+		"state 0, transition 0: target state 2 out of range",
+	}
+
+	err := p.Validate()
+	verr, ok := err.(*pda.ValidationError)
+	if !ok {
+		t.Fatalf("got error %v, want *ValidationError", err)
+	}
+	if !reflect.DeepEqual(verr.Problems, want) {
+		t.Errorf("got %q, want %q", verr.Problems, want)
+	}
+
+	if err := brackets.Validate(); err != nil {
+		t.Errorf("got error %v for valid automaton", err)
+	}
+}
@@ -0,0 +1,306 @@
+package regex
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"github.com/paulgriffiths/gods/sets"
+	"hash/crc32"
+	"math"
+	"sort"
+	"unicode/utf8"
+)
+
+// binaryMagic identifies a precompiled regular expression, and
+// binaryVersion the version of its encoding.
+const (
+	binaryMagic   = "ARX"
+	binaryVersion = 2
+)
+
+// Errors returned by Load.
+var (
+	ErrBadFormat   = errors.New("regex: not a precompiled regular expression")
+	ErrBadVersion  = errors.New("regex: unsupported precompiled regular expression version")
+	ErrBadChecksum = errors.New("regex: precompiled regular expression checksum mismatch")
+)
+
+// MarshalBinary encodes the regular expression in a compact form
+// which Load can turn back into a working matcher without repeating
+// the compilation. The encoding holds the regular expression in
+// string form, its syntax tree after any case folding, which gives
+// the capture groups, and its minimized deterministic finite
+// automaton as a sparse transition table, preceded by a format
+// version and followed by a CRC-32 checksum:
+//
+//	"ARX" version
+//	len(pattern) pattern
+//	0, or 1 and the syntax tree in prefix order
+//	Q Qs len(alphabet) alphabet...
+//	accepting state bitmap
+//	for each state: n, then n pairs of (letter index delta, target)
+//	CRC-32 (IEEE) of all of the above, big-endian
+//
+// All integers except the version and checksum are unsigned
+// varints, and the alphabet is sorted and delta-encoded. Each node
+// of the syntax tree is written as its Op, followed by its rune
+// for a literal, its name for a group, and the number of
+// subexpressions for a concatenation, alternation or intersection,
+// and then by its subexpressions. The syntax tree is written as 0
+// for a regular expression without one, such as one from
+// CompileKeywords. If the automaton has a transition on a rune
+// outside its alphabet, or is otherwise not well-formed, the error
+// is a *dfa.ValidationError and nothing is encoded.
+func (r *Regex) MarshalBinary() ([]byte, error) {
+	d := r.d.Minimize()
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+
+	alphabet := d.S.Elements()
+	sort.Slice(alphabet, func(i, j int) bool { return alphabet[i] < alphabet[j] })
+	index := make(map[rune]int)
+	for i, letter := range alphabet {
+		index[letter] = i
+	}
+
+	buf := []byte(binaryMagic)
+	buf = append(buf, binaryVersion)
+	buf = binary.AppendUvarint(buf, uint64(len(r.src)))
+	buf = append(buf, r.src...)
+	if r.expr == nil {
+		buf = append(buf, 0)
+	} else {
+		buf = appendNode(append(buf, 1), r.expr)
+	}
+	buf = binary.AppendUvarint(buf, uint64(d.Q))
+	buf = binary.AppendUvarint(buf, uint64(d.Qs))
+	buf = binary.AppendUvarint(buf, uint64(len(alphabet)))
+	prev := rune(0)
+	for _, letter := range alphabet {
+		buf = binary.AppendUvarint(buf, uint64(letter-prev))
+		prev = letter
+	}
+
+	bitmap := make([]byte, (d.Q+7)/8)
+	for _, q := range d.F.Elements() {
+		bitmap[q/8] |= 1 << uint(q%8)
+	}
+	buf = append(buf, bitmap...)
+
+	for q := 0; q < d.Q; q++ {
+		indices := []int{}
+		for letter := range d.D[q] {
+			indices = append(indices, index[letter])
+		}
+		sort.Ints(indices)
+		buf = binary.AppendUvarint(buf, uint64(len(indices)))
+		prev := 0
+		for _, i := range indices {
+			buf = binary.AppendUvarint(buf, uint64(i-prev))
+			buf = binary.AppendUvarint(buf, uint64(d.D[q][alphabet[i]]))
+			prev = i
+		}
+	}
+
+	return binary.BigEndian.AppendUint32(buf, crc32.ChecksumIEEE(buf)), nil
+}
+
+// UnmarshalBinary sets the regular expression to one encoded by
+// MarshalBinary. If the encoded Dfa is not well-formed, the error is
+// a *dfa.ValidationError.
+func (r *Regex) UnmarshalBinary(data []byte) error {
+	if len(data) < len(binaryMagic)+1+crc32.Size ||
+		!bytes.HasPrefix(data, []byte(binaryMagic)) {
+		return ErrBadFormat
+	}
+	if data[len(binaryMagic)] != binaryVersion {
+		return ErrBadVersion
+	}
+	body, sum := data[:len(data)-crc32.Size], data[len(data)-crc32.Size:]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(sum) {
+		return ErrBadChecksum
+	}
+
+	dec := decoder{buf: body[len(binaryMagic)+1:]}
+	src := string(dec.bytes(dec.count()))
+	var expr *syntax.Node
+	switch dec.byte() {
+	case 0:
+	case 1:
+		expr = dec.node()
+	default:
+		return ErrBadFormat
+	}
+	q := dec.count()
+	qs := dec.int()
+
+	alphabet := make([]rune, dec.count())
+	prev := rune(0)
+	for i := range alphabet {
+		alphabet[i] = prev + rune(dec.uvarint())
+		prev = alphabet[i]
+	}
+
+	accepts := sets.NewSetInt()
+	bitmap := dec.bytes((q + 7) / 8)
+	for state := 0; state < q && dec.err == nil; state++ {
+		if bitmap[state/8]&(1<<uint(state%8)) != 0 {
+			accepts.Insert(state)
+		}
+	}
+
+	tfunc := make([]map[rune]int, 0, q)
+	for state := 0; state < q && dec.err == nil; state++ {
+		trans := make(map[rune]int)
+		n := dec.count()
+		i := 0
+		for j := 0; j < n && dec.err == nil; j++ {
+			i += dec.int()
+			to := dec.int()
+			if i >= len(alphabet) {
+				return ErrBadFormat
+			}
+			trans[alphabet[i]] = to
+		}
+		tfunc = append(tfunc, trans)
+	}
+
+	if dec.err != nil || len(dec.buf) != 0 {
+		return ErrBadFormat
+	}
+
+	d := dfa.Dfa{
+		Q:  q,
+		S:  sets.NewSetRune(alphabet...),
+		D:  tfunc,
+		Qs: qs,
+		F:  accepts,
+	}
+	if err := d.Validate(); err != nil {
+		return err
+	}
+
+	r.src = src
+	r.d = d
+	r.b = &byteDfa{}
+	r.p = &lazyProgram{}
+	r.expr = expr
+	return nil
+}
+
+// appendNode appends the encoding of a syntax tree, as described for
+// MarshalBinary, to buf.
+func appendNode(buf []byte, n *syntax.Node) []byte {
+	buf = binary.AppendUvarint(buf, uint64(n.Op))
+	switch n.Op {
+	case syntax.OpLiteral:
+		buf = binary.AppendUvarint(buf, uint64(n.Rune))
+	case syntax.OpGroup:
+		buf = binary.AppendUvarint(buf, uint64(len(n.Name)))
+		buf = append(buf, n.Name...)
+	case syntax.OpConcat, syntax.OpAlternate, syntax.OpIntersect:
+		buf = binary.AppendUvarint(buf, uint64(len(n.Sub)))
+	}
+	for _, sub := range n.Sub {
+		buf = appendNode(buf, sub)
+	}
+	return buf
+}
+
+// Load returns the regular expression encoded by MarshalBinary.
+func Load(data []byte) (*Regex, error) {
+	r := &Regex{}
+	if err := r.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// decoder reads values from a binary encoding, recording the first
+// error encountered. Once an error has occurred, all subsequent
+// reads return zero values.
+type decoder struct {
+	buf []byte
+	err error
+}
+
+func (d *decoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.err = ErrBadFormat
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+// int reads an unsigned varint which must fit in an int32.
+func (d *decoder) int() int {
+	v := d.uvarint()
+	if v > math.MaxInt32 {
+		d.err = ErrBadFormat
+		return 0
+	}
+	return int(v)
+}
+
+// count reads a count of items, each of which occupies at least one
+// byte, and so can be no greater than the length of the remaining
+// data.
+func (d *decoder) count() int {
+	v := d.int()
+	if v > len(d.buf) {
+		d.err = ErrBadFormat
+		return 0
+	}
+	return v
+}
+
+// byte reads a single byte.
+func (d *decoder) byte() byte {
+	return d.bytes(1)[0]
+}
+
+// node reads a syntax tree encoded by appendNode, checking that each
+// node has the number of subexpressions its Op requires.
+func (d *decoder) node() *syntax.Node {
+	n := &syntax.Node{Op: syntax.Op(d.int())}
+	subs := 0
+	switch n.Op {
+	case syntax.OpNoMatch, syntax.OpEmptyMatch:
+	case syntax.OpLiteral:
+		n.Rune = rune(d.int())
+		if !utf8.ValidRune(n.Rune) {
+			d.err = ErrBadFormat
+		}
+	case syntax.OpGroup:
+		n.Name = string(d.bytes(d.count()))
+		subs = 1
+	case syntax.OpStar, syntax.OpComplement:
+		subs = 1
+	case syntax.OpConcat, syntax.OpAlternate, syntax.OpIntersect:
+		subs = d.count()
+	default:
+		d.err = ErrBadFormat
+	}
+	for i := 0; i < subs && d.err == nil; i++ {
+		n.Sub = append(n.Sub, d.node())
+	}
+	return n
+}
+
+func (d *decoder) bytes(n int) []byte {
+	if d.err != nil || n > len(d.buf) {
+		d.err = ErrBadFormat
+		return make([]byte, n)
+	}
+	b := d.buf[:n]
+	d.buf = d.buf[n:]
+	return b
+}
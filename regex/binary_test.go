@@ -0,0 +1,135 @@
+package regex_test
+
+import (
+	"github.com/paulgriffiths/automata/regex"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalBinary(t *testing.T) {
+	testCases := []string{
+		"a",
+		"a*",
+		"(a|b)*abb",
+		"aa(a|b)*bb",
+		"((a|b)(1|2|3)*(c|d))*",
+		"ää*ö|ü",
+		"a&b",
+	}
+
+	for n, tc := range testCases {
+		r := regex.CompileWithOptions(tc, regex.Options{Backend: regex.Derivatives})
+		data, err := r.MarshalBinary()
+		if err != nil {
+			t.Errorf("case %d, couldn't marshal: %v", n+1, err)
+			continue
+		}
+		loaded, err := regex.Load(data)
+		if err != nil {
+			t.Errorf("case %d, couldn't load: %v", n+1, err)
+			continue
+		}
+		if loaded.String() != tc {
+			t.Errorf("case %d, got source %q, want %q",
+				n+1, loaded.String(), tc)
+		}
+		if q, want := loaded.Dfa().Q, r.Dfa().Minimize().Q; q != want {
+			t.Errorf("case %d, got %d states, want %d", n+1, q, want)
+		}
+		for _, s := range allStrings("ab12cdäöü", 4) {
+			if got, want := loaded.Match(s), r.Match(s); got != want {
+				t.Errorf("case %d, input %q, got %t, want %t",
+					n+1, s, got, want)
+			}
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	data, err := regex.Compile("(a|b)*abb").MarshalBinary()
+	if err != nil {
+		t.Fatalf("couldn't marshal: %v", err)
+	}
+
+	corrupt := append([]byte{}, data...)
+	corrupt[len(corrupt)/2] ^= 0xff
+	version := append([]byte{}, data...)
+	version[3]++
+
+	testCases := []struct {
+		data []byte
+		err  error
+	}{
+		{nil, regex.ErrBadFormat},
+		{[]byte("ARX"), regex.ErrBadFormat},
+		{append([]byte("XYZ"), data[3:]...), regex.ErrBadFormat},
+		{version, regex.ErrBadVersion},
+		{corrupt, regex.ErrBadChecksum},
+		{data[:len(data)-1], regex.ErrBadChecksum},
+	}
+
+	for n, tc := range testCases {
+		if _, err := regex.Load(tc.data); err != tc.err {
+			t.Errorf("case %d, got %v, want %v", n+1, err, tc.err)
+		}
+	}
+}
+
+func TestMarshalBinarySubmatches(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		opts    regex.Options
+		input   string
+		repl    string
+		result  string
+	}{
+		{"(?P<w>aa*)b", regex.Options{}, "xaab", "[$w]", "x[aa]"},
+		{"(a|b)(1|2)*", regex.Options{}, "a12b", "<$2$1>", "<2a><b>"},
+		{"(?P<w>aa*)B", regex.Options{FoldCase: true}, "xAaBab", "[${w}]", "x[Aa][a]"},
+		{"(a&~(aa))b*", regex.Options{Backend: regex.Derivatives}, "abbaab", "$1", "aaa"},
+	}
+
+	for n, tc := range testCases {
+		r := regex.CompileWithOptions(tc.pattern, tc.opts)
+		data, err := r.MarshalBinary()
+		if err != nil {
+			t.Errorf("case %d, couldn't marshal: %v", n+1, err)
+			continue
+		}
+		loaded, err := regex.Load(data)
+		if err != nil {
+			t.Errorf("case %d, couldn't load: %v", n+1, err)
+			continue
+		}
+
+		if got, want := loaded.NumSubexp(), r.NumSubexp(); got != want {
+			t.Errorf("case %d, got %d groups, want %d", n+1, got, want)
+		}
+		if got, want := loaded.SubexpNames(), r.SubexpNames(); !reflect.DeepEqual(got, want) {
+			t.Errorf("case %d, got names %q, want %q", n+1, got, want)
+		}
+		got, want := loaded.FindStringSubmatch(tc.input), r.FindStringSubmatch(tc.input)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("case %d, got submatches %q, want %q", n+1, got, want)
+		}
+		if s := loaded.ReplaceAllString(tc.input, tc.repl); s != tc.result {
+			t.Errorf("case %d, got %q, want %q", n+1, s, tc.result)
+		}
+	}
+
+	keywords := regex.CompileKeywords([]string{"if", "else"})
+	data, err := keywords.MarshalBinary()
+	if err != nil {
+		t.Fatalf("couldn't marshal keywords: %v", err)
+	}
+	loaded, err := regex.Load(data)
+	if err != nil {
+		t.Fatalf("couldn't load keywords: %v", err)
+	}
+	if n := loaded.NumSubexp(); n != 0 {
+		t.Errorf("got %d groups for keywords, want 0", n)
+	}
+	if got := loaded.FindStringSubmatch("xelse"); !reflect.DeepEqual(got, []string{"else"}) {
+		t.Errorf("got keyword submatches %q, want %q", got, []string{"else"})
+	}
+}
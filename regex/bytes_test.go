@@ -0,0 +1,66 @@
+package regex_test
+
+import (
+	"github.com/paulgriffiths/automata/regex"
+	"sync"
+	"testing"
+)
+
+func TestMatchBytes(t *testing.T) {
+	testCases := []*regex.Regex{
+		regex.Compile("(a|b)*abb"),
+		regex.CompileWithOptions("~(a*)&(a|b)*", regex.Options{Backend: regex.Derivatives}),
+		regex.Compile("a*b*|ñ"),
+		regex.CompileKeywords([]string{"ñ", "€a", "añ€"}),
+	}
+
+	for n, r := range testCases {
+		for _, s := range allStrings("abñ€", 4) {
+			if got, want := r.MatchBytes([]byte(s)), r.Match(s); got != want {
+				t.Errorf("case %d, string %q, got %t, want %t", n+1, s, got, want)
+			}
+		}
+	}
+}
+
+func TestMatchBytesInvalidUTF8(t *testing.T) {
+	r := regex.Compile("ab*")
+	testCases := []struct {
+		input []byte
+		want  bool
+	}{
+		{[]byte("abb"), true},
+		{[]byte{'a', 0xff}, false},
+		{[]byte{'a', 'b', 0xc3}, false},
+		{[]byte{0x00, 'a'}, false},
+	}
+
+	for n, tc := range testCases {
+		if got := r.MatchBytes(tc.input); got != tc.want {
+			t.Errorf("case %d, got %t, want %t", n+1, got, tc.want)
+		}
+	}
+}
+
+func TestMatchBytesConcurrent(t *testing.T) {
+	data, err := regex.Compile("(a|b)*abb").MarshalBinary()
+	if err != nil {
+		t.Fatalf("couldn't marshal regex: %v", err)
+	}
+	r, err := regex.Load(data)
+	if err != nil {
+		t.Fatalf("couldn't load regex: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if !r.MatchBytes([]byte("babb")) {
+				t.Errorf("bytes %q not matched", "babb")
+			}
+		}()
+	}
+	wg.Wait()
+}
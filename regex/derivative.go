@@ -0,0 +1,264 @@
+package regex
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"github.com/paulgriffiths/gods/sets"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// term is a regular expression in the normal form maintained by the
+// smart constructors below. Two terms with the same key denote the
+// same language, and normalization guarantees that any expression
+// has only finitely many distinct derivatives, so the keys may be
+// used to identify the states of a deterministic finite automaton.
+type term struct {
+	op       syntax.Op
+	r        rune
+	sub      []*term
+	key      string
+	nullable bool
+}
+
+var (
+	noMatch    = &term{op: syntax.OpNoMatch, key: "0"}
+	emptyMatch = &term{op: syntax.OpEmptyMatch, key: "1", nullable: true}
+	anything   = newComplement(noMatch)
+)
+
+// derivativeDfa builds a deterministic finite automaton from a
+// regular expression syntax tree using Brzozowski derivatives. Each
+// state is a distinct derivative of the expression, and a state is
+// accepting if its derivative matches the empty string. Derivatives
+// which match no strings are omitted, along with any transitions
+// to them.
+func derivativeDfa(expr *syntax.Node) dfa.Dfa {
+	return derivativeDfaOver(expr, expr.Alphabet())
+}
+
+// derivativeDfaOver builds a deterministic finite automaton from a
+// regular expression syntax tree as described for derivativeDfa, but
+// over the provided alphabet, which must include the alphabet of the
+// expression, so that complements are taken with respect to it.
+func derivativeDfaOver(expr *syntax.Node, alphabet []rune) dfa.Dfa {
+	states := []*term{fromSyntax(expr)}
+	index := map[string]int{states[0].key: 0}
+	tfunc := []map[rune]int{}
+	accepts := sets.NewSetInt()
+
+	for i := 0; i < len(states); i++ {
+		trans := make(map[rune]int)
+		for _, letter := range alphabet {
+			next := derive(states[i], letter)
+			if next == noMatch {
+				continue
+			}
+			j, ok := index[next.key]
+			if !ok {
+				j = len(states)
+				states = append(states, next)
+				index[next.key] = j
+			}
+			trans[letter] = j
+		}
+		tfunc = append(tfunc, trans)
+		if states[i].nullable {
+			accepts.Insert(i)
+		}
+	}
+
+	return dfa.Dfa{
+		Q:  len(states),
+		S:  sets.NewSetRune(alphabet...),
+		D:  tfunc,
+		Qs: 0,
+		F:  accepts,
+	}
+}
+
+// derive returns the derivative of t with respect to the rune c,
+// that is, a term matching every string s such that c followed by
+// s is matched by t.
+func derive(t *term, c rune) *term {
+	switch t.op {
+	case syntax.OpLiteral:
+		if t.r == c {
+			return emptyMatch
+		}
+	case syntax.OpConcat:
+		d := newConcat(derive(t.sub[0], c), t.sub[1])
+		if t.sub[0].nullable {
+			return newSet(syntax.OpAlternate, d, derive(t.sub[1], c))
+		}
+		return d
+	case syntax.OpAlternate, syntax.OpIntersect:
+		subs := make([]*term, len(t.sub))
+		for i, sub := range t.sub {
+			subs[i] = derive(sub, c)
+		}
+		return newSet(t.op, subs...)
+	case syntax.OpStar:
+		return newConcat(derive(t.sub[0], c), t)
+	case syntax.OpComplement:
+		return newComplement(derive(t.sub[0], c))
+	}
+	return noMatch
+}
+
+// fromSyntax converts a regular expression syntax tree to a term.
+func fromSyntax(n *syntax.Node) *term {
+	switch n.Op {
+	case syntax.OpEmptyMatch:
+		return emptyMatch
+	case syntax.OpLiteral:
+		return newLiteral(n.Rune)
+	case syntax.OpConcat:
+		t := fromSyntax(n.Sub[len(n.Sub)-1])
+		for i := len(n.Sub) - 2; i >= 0; i-- {
+			t = newConcat(fromSyntax(n.Sub[i]), t)
+		}
+		return t
+	case syntax.OpAlternate, syntax.OpIntersect:
+		subs := make([]*term, len(n.Sub))
+		for i, sub := range n.Sub {
+			subs[i] = fromSyntax(sub)
+		}
+		return newSet(n.Op, subs...)
+	case syntax.OpGroup:
+		return fromSyntax(n.Sub[0])
+	case syntax.OpStar:
+		return newStar(fromSyntax(n.Sub[0]))
+	case syntax.OpComplement:
+		return newComplement(fromSyntax(n.Sub[0]))
+	}
+	return noMatch
+}
+
+func newLiteral(r rune) *term {
+	return &term{op: syntax.OpLiteral, r: r, key: strconv.QuoteRune(r)}
+}
+
+// newConcat returns the concatenation of two terms, eliminating
+// identities and annihilators, and associating to the right.
+func newConcat(a, b *term) *term {
+	switch {
+	case a == noMatch || b == noMatch:
+		return noMatch
+	case a == emptyMatch:
+		return b
+	case b == emptyMatch:
+		return a
+	case a.op == syntax.OpConcat:
+		return newConcat(a.sub[0], newConcat(a.sub[1], b))
+	}
+
+	return &term{
+		op:       syntax.OpConcat,
+		sub:      []*term{a, b},
+		key:      "(." + a.key + "," + b.key + ")",
+		nullable: a.nullable && b.nullable,
+	}
+}
+
+// newSet returns the union or intersection of the provided terms.
+// Nested operations of the same kind are flattened, and the operands
+// are deduplicated and sorted, so that the result is independent of
+// associativity, commutativity and idempotence.
+func newSet(op syntax.Op, terms ...*term) *term {
+	identity, annihilator := noMatch, anything
+	if op == syntax.OpIntersect {
+		identity, annihilator = anything, noMatch
+	}
+
+	unique := make(map[string]*term)
+	var flatten func(*term) bool
+	flatten = func(t *term) bool {
+		switch {
+		case t.key == annihilator.key:
+			return false
+		case t.key == identity.key:
+		case t.op == op:
+			for _, sub := range t.sub {
+				if !flatten(sub) {
+					return false
+				}
+			}
+		default:
+			unique[t.key] = t
+		}
+		return true
+	}
+
+	for _, t := range terms {
+		if !flatten(t) {
+			return annihilator
+		}
+	}
+
+	switch len(unique) {
+	case 0:
+		return identity
+	case 1:
+		for _, t := range unique {
+			return t
+		}
+	}
+
+	keys := make([]string, 0, len(unique))
+	for key := range unique {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := &term{op: op, nullable: op == syntax.OpIntersect}
+	for _, key := range keys {
+		t := unique[key]
+		result.sub = append(result.sub, t)
+		if op == syntax.OpAlternate {
+			result.nullable = result.nullable || t.nullable
+		} else {
+			result.nullable = result.nullable && t.nullable
+		}
+	}
+
+	separator := "|"
+	if op == syntax.OpIntersect {
+		separator = "&"
+	}
+	result.key = "(" + separator + strings.Join(keys, ",") + ")"
+
+	return result
+}
+
+// newStar returns the closure of a term.
+func newStar(a *term) *term {
+	switch {
+	case a.op == syntax.OpStar:
+		return a
+	case a == noMatch || a == emptyMatch:
+		return emptyMatch
+	}
+
+	return &term{
+		op:       syntax.OpStar,
+		sub:      []*term{a},
+		key:      "(*" + a.key + ")",
+		nullable: true,
+	}
+}
+
+// newComplement returns the complement of a term.
+func newComplement(a *term) *term {
+	if a.op == syntax.OpComplement {
+		return a.sub[0]
+	}
+
+	return &term{
+		op:       syntax.OpComplement,
+		sub:      []*term{a},
+		key:      "(~" + a.key + ")",
+		nullable: !a.nullable,
+	}
+}
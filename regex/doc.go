@@ -9,9 +9,18 @@ The Kleene star or closure operator has the highest precedence, and
 is right-associative. Concatenation has the next highest precedence,
 and the union operator has the lowest precedence. Arbitary parentheses
 may be used to group terms or alter the standard operator precedence.
+Each parenthesized group is also a capture group, which may be named
+with the syntax (?P<name>expr).
 
 The matching function attempts to match the entire string to the
 regular expression, e.g. the string "ha" will match the regular
-expresion "ha", but the string "that" will not.
+expresion "ha", but the string "that" will not. The Find, Replace and
+Split methods instead search for matching substrings.
+
+By default, regular expressions are compiled with the
+McNaughton-Yamada-Thompson algorithm followed by the subset
+construction. CompileWithOptions can instead select a backend which
+builds the automaton directly using Brzozowski derivatives, and which
+also supports the intersection (&) and complement (~) operators.
 */
 package regex
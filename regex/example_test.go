@@ -33,3 +33,37 @@ func Example() {
 	// String "aabbbba" doesn't match aa(a|b)*bb.
 	// Prefix "aabbbb" of string "aabbbba" matches aa(a|b)*bb.
 }
+
+func ExampleRegex_FindAllString() {
+	r := regex.Compile("a|ab")
+	fmt.Println(r.FindAllString("xabyaab", -1))
+
+	// Output:
+	// [ab a ab]
+}
+
+func ExampleSet_Matches() {
+	set, _ := regex.CompileSet([]string{"(a|b)*abb", "a*", "(a|b)*"})
+	fmt.Println(set.Matches("babb"), set.Matches("aa"), set.Matches("c"))
+
+	// Output:
+	// [0 2] [1 2] []
+}
+
+func ExampleCompileKeywords() {
+	r := regex.CompileKeywords([]string{"if", "else", "+=", "=="})
+	fmt.Println(r.FindAllString("if x == 1 else x += 2", -1))
+
+	// Output:
+	// [if == else +=]
+}
+
+func ExampleRegex_ReplaceAllString() {
+	r := regex.Compile("(?P<key>(a|b)(a|b)*)0(?P<value>(0|1)(0|1)*)")
+	fmt.Println(r.ReplaceAllString("ab01 ba011", "${value}0$key"))
+	fmt.Println(regex.Compile("00*").Split("a00b0c", -1))
+
+	// Output:
+	// 10ab 110ba
+	// [a b c]
+}
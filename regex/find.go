@@ -0,0 +1,96 @@
+package regex
+
+import "unicode/utf8"
+
+// FindStringIndex returns a two-element slice of integers giving the
+// location of the leftmost match of the regular expression in s,
+// such that the match is s[loc[0]:loc[1]]. Of the matches starting
+// at that location, the longest is chosen. A return value of nil
+// indicates no match.
+func (r *Regex) FindStringIndex(s string) []int {
+	return r.find(s, 0)
+}
+
+// FindString returns the text of the leftmost-longest match of the
+// regular expression in s. If there is no match, it returns the
+// empty string, which is also returned for an empty match; use
+// FindStringIndex to distinguish the two.
+func (r *Regex) FindString(s string) string {
+	loc := r.find(s, 0)
+	if loc == nil {
+		return ""
+	}
+	return s[loc[0]:loc[1]]
+}
+
+// FindAllStringIndex returns the locations of successive
+// non-overlapping leftmost-longest matches of the regular expression
+// in s, as described for FindStringIndex. If n is non-negative, at
+// most n matches are returned. Empty matches immediately following a
+// preceding match are ignored. A return value of nil indicates no
+// match.
+func (r *Regex) FindAllStringIndex(s string, n int) [][]int {
+	var result [][]int
+	prevEnd := -1
+
+	for pos := 0; pos <= len(s) && (n < 0 || len(result) < n); {
+		loc := r.find(s, pos)
+		if loc == nil {
+			break
+		}
+
+		if loc[0] != loc[1] || loc[0] != prevEnd {
+			result = append(result, loc)
+			prevEnd = loc[1]
+		}
+
+		if loc[1] > loc[0] {
+			pos = loc[1]
+		} else if loc[1] < len(s) {
+			_, size := utf8.DecodeRuneInString(s[loc[1]:])
+			pos = loc[1] + size
+		} else {
+			break
+		}
+	}
+
+	return result
+}
+
+// FindAllString returns the text of successive non-overlapping
+// leftmost-longest matches of the regular expression in s, as
+// described for FindAllStringIndex.
+func (r *Regex) FindAllString(s string, n int) []string {
+	var result []string
+	for _, loc := range r.FindAllStringIndex(s, n) {
+		result = append(result, s[loc[0]:loc[1]])
+	}
+	return result
+}
+
+// find returns the location of the leftmost-longest match in s
+// starting at or after byte offset start, or nil if there is none.
+func (r *Regex) find(s string, start int) []int {
+	for i := start; i <= len(s); {
+		if ok, n := r.longest(s[i:]); ok {
+			return []int{i, i + n}
+		}
+		if i == len(s) {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		i += size
+	}
+	return nil
+}
+
+// longest returns true and the length of the longest prefix of s
+// matching the regular expression, or false and zero if there is
+// none. Unlike MatchPrefix, it considers the empty prefix of a
+// non-empty string.
+func (r *Regex) longest(s string) (bool, int) {
+	if ok, n := r.d.AcceptsPrefix(s); ok {
+		return true, n
+	}
+	return r.d.F.Contains(r.d.Qs), 0
+}
@@ -0,0 +1,123 @@
+package regex_test
+
+import (
+	"github.com/paulgriffiths/automata/regex"
+	"reflect"
+	stdregexp "regexp"
+	"testing"
+)
+
+func TestFindStringIndex(t *testing.T) {
+	testCases := []struct {
+		rx, s string
+		loc   []int
+	}{
+		{"abb", "aabbab", []int{1, 4}},
+		{"a*", "", []int{0, 0}},
+		{"a*", "baa", []int{0, 0}},
+		{"aa*", "baa", []int{1, 3}},
+		{"(a|ab)(c|bcd)", "xabcd", []int{1, 5}},
+		{"b", "ééb", []int{4, 5}},
+		{"éé*", "xééy", []int{1, 5}},
+		{"c", "ab", nil},
+		{"ab", "", nil},
+	}
+
+	for n, tc := range testCases {
+		r := regex.Compile(tc.rx)
+		if loc := r.FindStringIndex(tc.s); !reflect.DeepEqual(loc, tc.loc) {
+			t.Errorf("case %d, got %v, want %v", n+1, loc, tc.loc)
+		}
+	}
+}
+
+func TestFindString(t *testing.T) {
+	testCases := []struct {
+		rx, s, result string
+	}{
+		{"(a|b)*abb", "ccabababbcc", "abababb"},
+		{"1(0|1)*", "2100112", "10011"},
+		{"x", "abc", ""},
+	}
+
+	for n, tc := range testCases {
+		r := regex.Compile(tc.rx)
+		if s := r.FindString(tc.s); s != tc.result {
+			t.Errorf("case %d, got %q, want %q", n+1, s, tc.result)
+		}
+	}
+}
+
+func TestFindAllString(t *testing.T) {
+	testCases := []struct {
+		rx, s  string
+		n      int
+		result []string
+	}{
+		{"ab", "abcabdab", -1, []string{"ab", "ab", "ab"}},
+		{"ab", "abcabdab", 2, []string{"ab", "ab"}},
+		{"ab", "abcabdab", 0, nil},
+		{"a*", "baaab", -1, []string{"", "aaa", ""}},
+		{"a*", "", -1, []string{""}},
+		{"a|b*", "abbc", -1, []string{"a", "bb", ""}},
+		{"x", "abc", -1, nil},
+	}
+
+	for n, tc := range testCases {
+		r := regex.Compile(tc.rx)
+		if s := r.FindAllString(tc.s, tc.n); !reflect.DeepEqual(s, tc.result) {
+			t.Errorf("case %d, got %q, want %q", n+1, s, tc.result)
+		}
+	}
+}
+
+// The search functions should agree with the leftmost-longest
+// semantics of the standard library's POSIX regular expressions.
+func TestFindAgreesWithPOSIX(t *testing.T) {
+	patterns := []string{
+		"a", "ab", "a*", "ab*", "(ab)*", "a|b", "a|ab", "(a|b)*b",
+		"aa*b*", "(aa|b)(aa|b)*", "(a|ab)(c|bcd)", "b(a|b)*a",
+	}
+
+	for _, pattern := range patterns {
+		r := regex.Compile(pattern)
+		std := stdregexp.MustCompilePOSIX(pattern)
+		for _, s := range allStrings("abc", 5) {
+			got, want := r.FindAllStringIndex(s, -1), std.FindAllStringIndex(s, -1)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("pattern %q, input %q, got %v, want %v",
+					pattern, s, got, want)
+			}
+		}
+	}
+}
+
+func TestFoldCase(t *testing.T) {
+	testCases := []struct {
+		rx, s  string
+		result bool
+	}{
+		{"abc", "ABC", true},
+		{"abc", "aBc", true},
+		{"ABC", "abc", true},
+		{"k", "K", true}, // Kelvin sign
+		{"a*b", "AaAB", true},
+		{"a1", "A1", true},
+		{"ab", "AC", false},
+	}
+
+	for _, backend := range []regex.Backend{regex.Thompson, regex.Derivatives} {
+		for n, tc := range testCases {
+			r := regex.CompileWithOptions(tc.rx,
+				regex.Options{Backend: backend, FoldCase: true})
+			if m := r.Match(tc.s); m != tc.result {
+				t.Errorf("backend %d, case %d, got %t, want %t",
+					backend, n+1, m, tc.result)
+			}
+		}
+	}
+
+	if regex.Compile("abc").Match("ABC") {
+		t.Errorf("matched without FoldCase")
+	}
+}
@@ -0,0 +1,36 @@
+package regex
+
+import (
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"sort"
+	"unicode"
+)
+
+// foldCase returns a copy of the syntax tree in which each literal
+// is replaced by the alternation of all the runes equivalent to it
+// under Unicode simple case folding, so that the expression matches
+// without regard to case.
+func foldCase(expr *syntax.Node) *syntax.Node {
+	if expr.Op == syntax.OpLiteral {
+		variants := []rune{expr.Rune}
+		for f := unicode.SimpleFold(expr.Rune); f != expr.Rune; f = unicode.SimpleFold(f) {
+			variants = append(variants, f)
+		}
+		if len(variants) == 1 {
+			return &syntax.Node{Op: syntax.OpLiteral, Rune: expr.Rune}
+		}
+
+		sort.Slice(variants, func(i, j int) bool { return variants[i] < variants[j] })
+		alt := &syntax.Node{Op: syntax.OpAlternate}
+		for _, r := range variants {
+			alt.Sub = append(alt.Sub, &syntax.Node{Op: syntax.OpLiteral, Rune: r})
+		}
+		return alt
+	}
+
+	folded := &syntax.Node{Op: expr.Op, Rune: expr.Rune, Name: expr.Name}
+	for _, sub := range expr.Sub {
+		folded.Sub = append(folded.Sub, foldCase(sub))
+	}
+	return folded
+}
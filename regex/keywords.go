@@ -0,0 +1,17 @@
+package regex
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"strings"
+)
+
+// CompileKeywords compiles a regular expression matching exactly the
+// provided keywords, equivalent to their alternation. The automaton
+// is built directly as a trie with dfa.FromKeywords and then
+// minimized, which is much faster than compiling the alternation for
+// large numbers of keywords. The keywords are taken literally, and
+// may contain any runes. The String method returns the keywords
+// joined by |.
+func CompileKeywords(words []string) *Regex {
+	return newRegex(strings.Join(words, "|"), dfa.FromKeywords(words).Minimize())
+}
@@ -0,0 +1,58 @@
+package regex_test
+
+import (
+	"github.com/paulgriffiths/automata/regex"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCompileKeywords(t *testing.T) {
+	testCases := [][]string{
+		{"he", "she", "his", "hers"},
+		{"a", "ab", "abc", "b"},
+		{"ab", "ba", "ab"},
+		{"aaa"},
+	}
+
+	for n, tc := range testCases {
+		k := regex.CompileKeywords(tc)
+		r := regex.Compile(strings.Join(tc, "|"))
+		if k.String() != r.String() {
+			t.Errorf("case %d, got %q, want %q", n+1, k.String(), r.String())
+		}
+		if k.Dfa().Q != r.Dfa().Minimize().Q {
+			t.Errorf("case %d, got %d states, want %d",
+				n+1, k.Dfa().Q, r.Dfa().Minimize().Q)
+		}
+		for _, s := range allStrings("abehirs", 4) {
+			if k.Match(s) != r.Match(s) {
+				t.Errorf("case %d, string %q, got %t, want %t",
+					n+1, s, k.Match(s), r.Match(s))
+			}
+			got, want := k.FindAllStringIndex(s, -1), r.FindAllStringIndex(s, -1)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("case %d, string %q, got %v, want %v",
+					n+1, s, got, want)
+			}
+		}
+	}
+}
+
+func TestCompileKeywordsLiteral(t *testing.T) {
+	k := regex.CompileKeywords([]string{"a*b", "c|d", "ñ"})
+	testCases := []struct {
+		input string
+		want  []string
+	}{
+		{"aab a*b c c|d", []string{"a*b", "c|d"}},
+		{"año", []string{"ñ"}},
+		{"ab", nil},
+	}
+
+	for n, tc := range testCases {
+		if got := k.FindAllString(tc.input, -1); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("case %d, got %q, want %q", n+1, got, tc.want)
+		}
+	}
+}
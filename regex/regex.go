@@ -2,14 +2,63 @@ package regex
 
 import (
 	"github.com/paulgriffiths/automata/dfa"
-	"github.com/paulgriffiths/automata/nfa"
-	"github.com/paulgriffiths/goeval/lar"
-	"strings"
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"sync"
 )
 
 // Regex represents a compiled regular expression.
 type Regex struct {
-	d dfa.Dfa
+	src  string
+	d    dfa.Dfa
+	b    *byteDfa
+	p    *lazyProgram
+	expr *syntax.Node
+}
+
+// byteDfa holds the byte-level automaton for a regular expression,
+// which is only built if it is needed.
+type byteDfa struct {
+	once sync.Once
+	d    dfa.Dfa
+}
+
+// lazyProgram holds the program for finding the capture groups of a
+// regular expression, which is only compiled if it is needed. Once
+// compiled, it is never modified, so it can be shared by concurrent
+// searches.
+type lazyProgram struct {
+	once sync.Once
+	p    *program
+}
+
+// newRegex returns a Regex with the provided source text and
+// automaton.
+func newRegex(src string, d dfa.Dfa) *Regex {
+	return &Regex{src: src, d: d, b: &byteDfa{}, p: &lazyProgram{}}
+}
+
+// Backend selects the algorithm used to construct the deterministic
+// finite automaton for a regular expression.
+type Backend int
+
+// Available backends.
+const (
+	// Thompson builds a nondeterministic finite automaton using the
+	// McNaughton-Yamada-Thompson algorithm, and converts it to a
+	// deterministic finite automaton using the subset construction.
+	Thompson Backend = iota
+
+	// Derivatives builds a deterministic finite automaton directly
+	// from the regular expression using Brzozowski derivatives. It
+	// is the only backend to support the intersection and
+	// complement operators.
+	Derivatives
+)
+
+// Options controls the compilation of a regular expression.
+type Options struct {
+	Backend  Backend // Construction algorithm
+	FoldCase bool    // Match letters without regard to case
 }
 
 // Match tests if the supplied string matches the regular expression.
@@ -25,79 +74,61 @@ func (r *Regex) MatchPrefix(s string) (bool, int) {
 	return r.d.AcceptsPrefix(s)
 }
 
-// Compile compiles a regular expression provided in string form.
-func Compile(r string) *Regex {
-	lar, err := lar.NewLookaheadReader(strings.NewReader(r))
-	if err != nil {
-		return nil
-	}
-
-	expr := getExpr(&lar)
-	if expr == nil || !lar.EndOfInput() {
-		return nil
-	}
-
-	rx := Regex{(*expr).ToDfa()}
-	return &rx
+// MatchBytes tests if the supplied bytes match the regular
+// expression. Unlike Match, which decodes invalid UTF-8 as
+// utf8.RuneError, it runs a byte-level automaton which never matches
+// invalid UTF-8, so arbitrary binary data can be matched
+// deterministically. The byte-level automaton is built and minimized
+// the first time MatchBytes is called.
+func (r *Regex) MatchBytes(b []byte) bool {
+	r.b.once.Do(func() {
+		r.b.d = r.d.ToUTF8().Minimize()
+	})
+	return r.b.d.AcceptsBytes(b)
 }
 
-func getExpr(lar *lar.LookaheadReader) *nfa.Nfa {
-	concat := getConcat(lar)
-	if concat == nil {
-		return nil
-	}
-
-	for lar.MatchOneOf('|') {
-		next := getConcat(lar)
-		if next == nil {
-			return nil
-		}
-		temp := nfa.NewUnionNfa(*concat, *next)
-		concat = &temp
-	}
-
-	return concat
+// String returns the source text used to compile the regular
+// expression.
+func (r *Regex) String() string {
+	return r.src
 }
 
-func getConcat(lar *lar.LookaheadReader) *nfa.Nfa {
-	closure := getClosure(lar)
-	if closure == nil {
-		return nil
-	}
-	for next := getClosure(lar); next != nil; next = getClosure(lar) {
-		temp := nfa.NewConcatNfa(*closure, *next)
-		closure = &temp
-	}
+// Dfa returns the deterministic finite automaton which implements
+// the regular expression.
+func (r *Regex) Dfa() dfa.Dfa {
+	return r.d
+}
 
-	return closure
+// Compile compiles a regular expression provided in string form.
+func Compile(r string) *Regex {
+	return CompileWithOptions(r, Options{})
 }
 
-func getClosure(lar *lar.LookaheadReader) *nfa.Nfa {
-	term := getTerm(lar)
-	if term == nil {
+// CompileWithOptions compiles a regular expression provided in string
+// form using the specified options.
+func CompileWithOptions(r string, opts Options) *Regex {
+	expr, err := syntax.Parse(r)
+	if err != nil {
 		return nil
 	}
-	if lar.MatchOneOf('*') {
-		closure := nfa.NewClosureNfa(*term)
-		return &closure
+	if opts.FoldCase {
+		expr = foldCase(expr)
 	}
-	return term
-}
 
-func getTerm(lar *lar.LookaheadReader) *nfa.Nfa {
-	switch {
-	case lar.MatchLetter():
-		letter := nfa.NewRuneNfa(lar.Result.Value[0])
-		return &letter
-	case lar.MatchDigit():
-		digit := nfa.NewRuneNfa(lar.Result.Value[0])
-		return &digit
-	case lar.MatchOneOf('('):
-		expr := getExpr(lar)
-		if expr == nil || !lar.MatchOneOf(')') {
+	var re *Regex
+	switch opts.Backend {
+	case Thompson:
+		n, ok := thompson(expr)
+		if !ok {
 			return nil
 		}
-		return expr
+		re = newRegex(r, n.ToDfa())
+	case Derivatives:
+		re = newRegex(r, derivativeDfa(expr))
+	default:
+		return nil
 	}
-	return nil
+
+	re.expr = expr
+	return re
 }
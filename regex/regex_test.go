@@ -1,6 +1,7 @@
 package regex_test
 
 import (
+	"github.com/paulgriffiths/automata/dfa"
 	"github.com/paulgriffiths/automata/regex"
 	"testing"
 )
@@ -143,3 +144,111 @@ func TestBadRegex(t *testing.T) {
 		}
 	}
 }
+
+// allStrings returns all strings over the provided alphabet with
+// lengths up to and including n.
+func allStrings(alphabet string, n int) []string {
+	result := []string{""}
+	last := []string{""}
+	for i := 0; i < n; i++ {
+		next := []string{}
+		for _, s := range last {
+			for _, r := range alphabet {
+				next = append(next, s+string(r))
+			}
+		}
+		result = append(result, next...)
+		last = next
+	}
+	return result
+}
+
+func TestDerivativesAgreeWithThompson(t *testing.T) {
+	testCases := []string{
+		"a",
+		"ab",
+		"a*",
+		"a*b*",
+		"a|b",
+		"(a|b)*abb",
+		"aa(a|b)*bb",
+		"((aa|bb)(aa|bb))*",
+		"(a*b*)*",
+		"(a|ab)(c|bcd)",
+		"((a|b)(1|2)*(a|b))*",
+	}
+
+	opts := regex.Options{Backend: regex.Derivatives}
+	for n, tc := range testCases {
+		thompson := regex.Compile(tc)
+		derivs := regex.CompileWithOptions(tc, opts)
+		if thompson == nil || derivs == nil {
+			t.Errorf("case %d, couldn't compile regex %q", n+1, tc)
+			continue
+		}
+		for _, d := range []dfa.Dfa{thompson.Dfa(), derivs.Dfa(),
+			thompson.Dfa().Minimize()} {
+			if err := d.Validate(); err != nil {
+				t.Errorf("case %d, invalid Dfa: %v", n+1, err)
+			}
+		}
+		if q, p := derivs.Dfa().Q, thompson.Dfa().Q; q > p {
+			t.Errorf("case %d, derivatives gave %d states, thompson %d",
+				n+1, q, p)
+		}
+		for _, s := range allStrings("ab12cd", 4) {
+			if d, t1 := derivs.Match(s), thompson.Match(s); d != t1 {
+				t.Errorf("case %d, input %q, got %t, want %t",
+					n+1, s, d, t1)
+			}
+		}
+	}
+}
+
+func TestDerivativesIntersectComplement(t *testing.T) {
+	testCases := []struct {
+		rx, s  string
+		result bool
+	}{
+		{"(a|b)*a(a|b)*&(a|b)*b(a|b)*", "ab", true},
+		{"(a|b)*a(a|b)*&(a|b)*b(a|b)*", "ba", true},
+		{"(a|b)*a(a|b)*&(a|b)*b(a|b)*", "aaa", false},
+		{"(a|b)*a(a|b)*&(a|b)*b(a|b)*", "", false},
+		{"a*&b*", "", true},
+		{"a*&b*", "a", false},
+		{"a&b", "a", false},
+		{"~a", "", true},
+		{"~a", "a", false},
+		{"~a", "aa", true},
+		{"~(a|b)*abb", "abb", false},
+		{"~((a|b)*abb)", "babb", false},
+		{"~((a|b)*abb)", "abba", true},
+		{"~a*", "aaa", false},
+		{"~~a", "a", true},
+		{"~~a", "aa", false},
+		{"(a|b)*&~((a|b)*bb(a|b)*)", "ababa", true},
+		{"(a|b)*&~((a|b)*bb(a|b)*)", "abbab", false},
+		{"ab&a|b", "b", true},
+		{"ab&a|b", "ab", false},
+	}
+
+	opts := regex.Options{Backend: regex.Derivatives}
+	for n, tc := range testCases {
+		r := regex.CompileWithOptions(tc.rx, opts)
+		if r == nil {
+			t.Errorf("case %d, couldn't compile regex %q", n+1, tc.rx)
+			continue
+		}
+		if result := r.Match(tc.s); result != tc.result {
+			t.Errorf("case %d, got %t, want %t", n+1, result, tc.result)
+		}
+	}
+}
+
+func TestThompsonRejectsIntersectComplement(t *testing.T) {
+	for n, tc := range []string{"a&b", "~a", "a(b&c)", "(~a)*"} {
+		if r := regex.Compile(tc); r != nil {
+			t.Errorf("case %d, unexpectedly compiled regex %q", n+1, tc)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package regex
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ReplaceAllString returns a copy of src in which each match of the
+// regular expression, as found by FindAllStringIndex, is replaced by
+// the template repl. Within repl, $name and ${name} are replaced by
+// the text of the capture group with that name or number, or by the
+// empty string if there is no such group or it took no part in the
+// match, and $$ is replaced by a single $. In the $name form, name is
+// as long as possible, so $1x is equivalent to ${1x} and not ${1}x.
+func (r *Regex) ReplaceAllString(src, repl string) string {
+	return r.replaceAll(src, func(b *strings.Builder, loc []int) {
+		r.expand(b, repl, src, r.submatches(src, loc[0], loc[1]))
+	})
+}
+
+// ReplaceAllLiteralString returns a copy of src in which each match
+// of the regular expression is replaced by repl, which is used as it
+// is, without expansion.
+func (r *Regex) ReplaceAllLiteralString(src, repl string) string {
+	return r.replaceAll(src, func(b *strings.Builder, loc []int) {
+		b.WriteString(repl)
+	})
+}
+
+// ReplaceAllStringFunc returns a copy of src in which each match of
+// the regular expression is replaced by the return value of repl
+// applied to the matched text, which is used without expansion.
+func (r *Regex) ReplaceAllStringFunc(src string, repl func(string) string) string {
+	return r.replaceAll(src, func(b *strings.Builder, loc []int) {
+		b.WriteString(repl(src[loc[0]:loc[1]]))
+	})
+}
+
+// replaceAll returns a copy of src in which the text of each match of
+// the regular expression is replaced by whatever repl writes for it.
+func (r *Regex) replaceAll(src string, repl func(*strings.Builder, []int)) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range r.FindAllStringIndex(src, -1) {
+		b.WriteString(src[last:loc[0]])
+		repl(&b, loc)
+		last = loc[1]
+	}
+	b.WriteString(src[last:])
+	return b.String()
+}
+
+// Split slices s into substrings separated by the matches of the
+// regular expression, and returns the substrings between them, as
+// regexp.Regexp.Split does. If n is negative, all the substrings are
+// returned. If n is zero, it returns nil. Otherwise, at most n
+// substrings are returned, the last being the unsplit remainder.
+func (r *Regex) Split(s string, n int) []string {
+	if n == 0 {
+		return nil
+	}
+	if len(s) == 0 {
+		return []string{""}
+	}
+
+	result := []string{}
+	begin, end := 0, 0
+	for _, loc := range r.FindAllStringIndex(s, n) {
+		if n > 0 && len(result) == n-1 {
+			break
+		}
+		end = loc[0]
+		if loc[1] != 0 {
+			result = append(result, s[begin:end])
+		}
+		begin = loc[1]
+	}
+	if end != len(s) {
+		result = append(result, s[begin:])
+	}
+	return result
+}
+
+// expand writes the template to the builder, replacing references to
+// capture groups with their text in src, whose locations are in loc.
+func (r *Regex) expand(b *strings.Builder, template, src string, loc []int) {
+	for len(template) > 0 {
+		i := strings.IndexByte(template, '$')
+		if i < 0 {
+			break
+		}
+		b.WriteString(template[:i])
+		template = template[i:]
+
+		if len(template) > 1 && template[1] == '$' {
+			b.WriteByte('$')
+			template = template[2:]
+			continue
+		}
+
+		name, rest, ok := extractName(template)
+		if !ok {
+			b.WriteByte('$')
+			template = template[1:]
+			continue
+		}
+		template = rest
+
+		group, err := strconv.Atoi(name)
+		if err != nil {
+			group = r.SubexpIndex(name)
+		}
+		if group >= 0 && 2*group < len(loc) && loc[2*group] >= 0 {
+			b.WriteString(src[loc[2*group]:loc[2*group+1]])
+		}
+	}
+	b.WriteString(template)
+}
+
+// extractName returns the name in a reference to a capture group of
+// the form $name or ${name} at the start of the template, and the
+// rest of the template. It returns false if there is no well-formed
+// reference.
+func extractName(template string) (string, string, bool) {
+	brace := len(template) > 1 && template[1] == '{'
+	i := 1
+	if brace {
+		i = 2
+	}
+
+	start := i
+	for i < len(template) {
+		letter, size := utf8.DecodeRuneInString(template[i:])
+		if !unicode.IsLetter(letter) && !unicode.IsDigit(letter) && letter != '_' {
+			break
+		}
+		i += size
+	}
+	if i == start {
+		return "", "", false
+	}
+
+	name := template[start:i]
+	if brace {
+		if i >= len(template) || template[i] != '}' {
+			return "", "", false
+		}
+		i++
+	}
+	return name, template[i:], true
+}
@@ -0,0 +1,82 @@
+package regex_test
+
+import (
+	"github.com/paulgriffiths/automata/regex"
+	"reflect"
+	stdregexp "regexp"
+	"strings"
+	"testing"
+)
+
+func TestReplaceAllString(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		input   string
+		repl    string
+		want    string
+	}{
+		{"a(b*)", "xabbyaz", "<$1>", "x<bb>y<>z"},
+		{"a(b*)", "xabbyaz", "${1}1", "xbb1y1z"},
+		{"a(b*)", "xabbyaz", "$1x", "xyz"},
+		{"(?P<first>a)(?P<second>b)", "abab", "$second$first", "baba"},
+		{"(?P<first>a)(?P<second>b)", "abab", "${second}-${first}", "b-ab-a"},
+		{"ab", "abab", "$$", "$$"},
+		{"ab", "abab", "$", "$$"},
+		{"ab", "abab", "${", "${${"},
+		{"ab", "abab", "${0", "${0${0"},
+		{"ab", "abab", "[$0]", "[ab][ab]"},
+		{"ab", "abab", "$2", ""},
+		{"a*", "baaac", "-", "-b-c-"},
+		{"(a)|(b)", "ab", "[$1|$2]", "[a|][|b]"},
+	}
+
+	for n, tc := range testCases {
+		r := regex.Compile(tc.pattern)
+		if got := r.ReplaceAllString(tc.input, tc.repl); got != tc.want {
+			t.Errorf("case %d, got %q, want %q", n+1, got, tc.want)
+		}
+	}
+}
+
+func TestReplaceAgreesWithStdlib(t *testing.T) {
+	patterns := []string{"a", "ab*", "a*", "(a|b)*b", "a|ab", "(a)(b|c)"}
+	templates := []string{"", "x", "[$0]", "$1$2", "${1}x$$", "$name"}
+
+	for _, pattern := range patterns {
+		r := regex.Compile(pattern)
+		std := stdregexp.MustCompilePOSIX(pattern)
+		for _, s := range allStrings("abc", 4) {
+			for _, tmpl := range templates {
+				if got, want := r.ReplaceAllString(s, tmpl), std.ReplaceAllString(s, tmpl); got != want {
+					t.Errorf("pattern %q, input %q, template %q, got %q, want %q",
+						pattern, s, tmpl, got, want)
+				}
+			}
+			if got, want := r.ReplaceAllLiteralString(s, "$0"), std.ReplaceAllLiteralString(s, "$0"); got != want {
+				t.Errorf("pattern %q, input %q, got literal %q, want %q",
+					pattern, s, got, want)
+			}
+			if got, want := r.ReplaceAllStringFunc(s, strings.ToUpper), std.ReplaceAllStringFunc(s, strings.ToUpper); got != want {
+				t.Errorf("pattern %q, input %q, got func %q, want %q",
+					pattern, s, got, want)
+			}
+		}
+	}
+}
+
+func TestSplit(t *testing.T) {
+	patterns := []string{"a", "ab*", "a*", "bb*", "(a|b)*b", "c"}
+
+	for _, pattern := range patterns {
+		r := regex.Compile(pattern)
+		std := stdregexp.MustCompilePOSIX(pattern)
+		for _, s := range allStrings("abc", 4) {
+			for _, n := range []int{-1, 0, 1, 2, 3} {
+				if got, want := r.Split(s, n), std.Split(s, n); !reflect.DeepEqual(got, want) {
+					t.Errorf("pattern %q, input %q, n %d, got %q, want %q",
+						pattern, s, n, got, want)
+				}
+			}
+		}
+	}
+}
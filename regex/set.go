@@ -0,0 +1,90 @@
+package regex
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"github.com/paulgriffiths/gods/sets"
+)
+
+// Set represents a set of regular expressions compiled into a single
+// deterministic finite automaton, so that a string can be tested
+// against all of them in one pass.
+type Set struct {
+	srcs []string
+	d    dfa.TaggedDfa
+}
+
+// CompileSet compiles a set of regular expressions provided in
+// string form. Each expression is compiled to a minimized Dfa using
+// the Derivatives backend, so all operators are supported. The Dfas
+// are joined by e-transitions from a new start state into an Nfa in
+// which the accepting states of the ith Dfa are tagged with i, and
+// the Nfa is converted to a TaggedDfa with the subset construction,
+// which is then minimized.
+func CompileSet(patterns []string) (*Set, error) {
+	b := nfa.NewBuilder()
+	start := b.AddState()
+	tags := []sets.SetInt{sets.NewSetInt()}
+
+	for i, pattern := range patterns {
+		expr, err := syntax.Parse(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("regex: pattern %d: %w", i, err)
+		}
+		d := derivativeDfa(expr).Minimize()
+
+		offset := b.AddState()
+		tags = append(tags, sets.NewSetInt())
+		for q := 1; q < d.Q; q++ {
+			b.AddState()
+			tags = append(tags, sets.NewSetInt())
+		}
+		for q, trans := range d.D {
+			for letter, to := range trans {
+				b.AddTransition(q+offset, letter, to+offset)
+			}
+		}
+		for _, q := range d.F.Elements() {
+			b.SetAccepting(q + offset)
+			tags[q+offset].Insert(i)
+		}
+		b.AddEpsilon(start, d.Qs+offset)
+	}
+
+	n, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Set{
+		srcs: append([]string{}, patterns...),
+		d:    n.ToTaggedDfa(tags).Minimize(),
+	}, nil
+}
+
+// Matches returns, in ascending order, the indices of the regular
+// expressions in the set which match the entire supplied string, or
+// nil if none of them do.
+func (s *Set) Matches(str string) []int {
+	return s.d.Tags(str)
+}
+
+// Len returns the number of regular expressions in the set.
+func (s *Set) Len() int {
+	return len(s.srcs)
+}
+
+// Patterns returns the source text of the regular expressions in the
+// set, in the order in which they were compiled.
+func (s *Set) Patterns() []string {
+	return append([]string{}, s.srcs...)
+}
+
+// Dfa returns the tagged deterministic finite automaton which
+// implements the set, in which each state is tagged with the indices
+// of the regular expressions matching the strings which reach it.
+func (s *Set) Dfa() dfa.TaggedDfa {
+	return s.d
+}
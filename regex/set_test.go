@@ -0,0 +1,99 @@
+package regex_test
+
+import (
+	"errors"
+	"github.com/paulgriffiths/automata/regex"
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"reflect"
+	"testing"
+)
+
+func TestSetMatches(t *testing.T) {
+	patterns := []string{
+		"(a|b)*abb",
+		"a*",
+		"(a|b)*",
+		"ab|ba",
+		"cc*",
+		"~(a*)&(a|b)*",
+	}
+
+	set, err := regex.CompileSet(patterns)
+	if err != nil {
+		t.Fatalf("couldn't compile set: %v", err)
+	}
+	if set.Len() != len(patterns) {
+		t.Errorf("got length %d, want %d", set.Len(), len(patterns))
+	}
+
+	testCases := []struct {
+		input   string
+		matches []int
+	}{
+		{"", []int{1, 2}},
+		{"a", []int{1, 2}},
+		{"aaa", []int{1, 2}},
+		{"ab", []int{2, 3, 5}},
+		{"ba", []int{2, 3, 5}},
+		{"abb", []int{0, 2, 5}},
+		{"babb", []int{0, 2, 5}},
+		{"ccc", []int{4}},
+		{"abc", nil},
+		{"d", nil},
+	}
+
+	for n, tc := range testCases {
+		if m := set.Matches(tc.input); !reflect.DeepEqual(m, tc.matches) {
+			t.Errorf("case %d, got %v, want %v", n+1, m, tc.matches)
+		}
+	}
+
+	// Matches must agree with matching each pattern in turn.
+	regexes := []*regex.Regex{}
+	for _, pattern := range patterns {
+		regexes = append(regexes, regex.CompileWithOptions(pattern,
+			regex.Options{Backend: regex.Derivatives}))
+	}
+	for _, s := range allStrings("abc", 5) {
+		var want []int
+		for i, r := range regexes {
+			if r.Match(s) {
+				want = append(want, i)
+			}
+		}
+		if got := set.Matches(s); !reflect.DeepEqual(got, want) {
+			t.Errorf("input %q, got %v, want %v", s, got, want)
+		}
+	}
+
+	if err := set.Dfa().Validate(); err != nil {
+		t.Errorf("invalid Dfa: %v", err)
+	}
+}
+
+func TestSetSharesStates(t *testing.T) {
+	// Identical patterns should not need any more states than one.
+	one, _ := regex.CompileSet([]string{"(a|b)*abb"})
+	three, _ := regex.CompileSet([]string{"(a|b)*abb", "(a|b)*abb", "(b|a)*abb"})
+	if q, p := three.Dfa().Q, one.Dfa().Q; q != p {
+		t.Errorf("got %d states, want %d", q, p)
+	}
+	if m := three.Matches("aabb"); !reflect.DeepEqual(m, []int{0, 1, 2}) {
+		t.Errorf("got %v, want [0 1 2]", m)
+	}
+}
+
+func TestCompileSetErrors(t *testing.T) {
+	_, err := regex.CompileSet([]string{"ab", "a|"})
+	if !errors.Is(err, syntax.ErrMissingOperand) {
+		t.Errorf("got %v, want %v", err, syntax.ErrMissingOperand)
+	}
+
+	set, err := regex.CompileSet(nil)
+	if err != nil {
+		t.Fatalf("couldn't compile empty set: %v", err)
+	}
+	if m := set.Matches(""); m != nil {
+		t.Errorf("got %v, want nil", m)
+	}
+}
@@ -0,0 +1,307 @@
+package regex
+
+import (
+	"github.com/paulgriffiths/automata/dfa"
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"unicode/utf8"
+)
+
+// NumSubexp returns the number of parenthesized subexpressions, or
+// capture groups, in the regular expression. Regular expressions
+// returned by CompileKeywords have none.
+func (r *Regex) NumSubexp() int {
+	if r.expr == nil {
+		return 0
+	}
+	return len(r.expr.Groups())
+}
+
+// SubexpNames returns the names of the capture groups in the regular
+// expression, named with the (?P<name>...) syntax. The name of the
+// ith group is element i, and element 0, for the whole expression,
+// is always the empty string, as are the names of unnamed groups.
+func (r *Regex) SubexpNames() []string {
+	names := []string{""}
+	if r.expr != nil {
+		for _, group := range r.expr.Groups() {
+			names = append(names, group.Name)
+		}
+	}
+	return names
+}
+
+// SubexpIndex returns the index of the first capture group with the
+// provided name, or -1 if there is none.
+func (r *Regex) SubexpIndex(name string) int {
+	if name == "" {
+		return -1
+	}
+	for i, n := range r.SubexpNames() {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// FindStringSubmatchIndex returns a slice of pairs of integers giving
+// the location of the leftmost-longest match of the regular
+// expression in s and of the capture groups within it, such that
+// group i is s[loc[2*i]:loc[2*i+1]]. Groups which took no part in the
+// match have locations of -1. A return value of nil indicates no
+// match.
+//
+// The automaton which finds the match cannot say where the groups
+// are, so they are found afterwards by a backtracking search within
+// the match which never tries the same part of the expression at the
+// same position twice, and so takes time linear in the length of the
+// match. Where a group could match in more than one way, earlier
+// alternatives are preferred, and repetitions match as many times as
+// possible, as in Perl. Groups within the operands of the & and ~
+// operators are never set.
+func (r *Regex) FindStringSubmatchIndex(s string) []int {
+	loc := r.find(s, 0)
+	if loc == nil {
+		return nil
+	}
+	return r.submatches(s, loc[0], loc[1])
+}
+
+// FindStringSubmatch returns the text of the leftmost-longest match
+// of the regular expression in s and of its capture groups, as
+// described for FindStringSubmatchIndex, with groups which took no
+// part in the match set to the empty string. A return value of nil
+// indicates no match.
+func (r *Regex) FindStringSubmatch(s string) []string {
+	loc := r.FindStringSubmatchIndex(s)
+	if loc == nil {
+		return nil
+	}
+	result := make([]string, len(loc)/2)
+	for i := range result {
+		if loc[2*i] >= 0 {
+			result[i] = s[loc[2*i]:loc[2*i+1]]
+		}
+	}
+	return result
+}
+
+// submatches returns the locations of the match s[start:end] and of
+// the capture groups within it.
+func (r *Regex) submatches(s string, start, end int) []int {
+	loc := make([]int, 2*(r.NumSubexp()+1))
+	for i := range loc {
+		loc[i] = -1
+	}
+	loc[0], loc[1] = start, end
+	if r.expr == nil {
+		return loc
+	}
+
+	r.p.once.Do(func() {
+		r.p.p = newProgram(r.expr)
+	})
+	if !r.p.p.run(s[:end], start, loc) {
+		for i := 2; i < len(loc); i++ {
+			loc[i] = -1
+		}
+	}
+	return loc
+}
+
+// instOp identifies the kind of an instruction in a program.
+type instOp int
+
+// Instruction kinds.
+const (
+	instFail  instOp = iota // Fails
+	instRune                // Matches r, then continues at next
+	instSplit               // Continues at next, or failing that at alt
+	instSave                // Records the position in slot, then continues at next
+	instDfa                 // Matches a prefix accepted by d, preferring longer ones
+	instMatch               // Succeeds at the end of the match
+)
+
+// inst is an instruction in a program.
+type inst struct {
+	op   instOp
+	r    rune
+	next int
+	alt  int
+	slot int
+	d    dfa.Dfa
+}
+
+// program is a regular expression compiled to instructions for
+// finding the locations of its capture groups. Operands of the & and
+// ~ operators are compiled to a single instDfa instruction, and so
+// capture nothing.
+type program struct {
+	insts []inst
+	start int
+
+	// base gives each instruction its first state number. An instDfa
+	// instruction has one state for each state of its Dfa, and every
+	// other instruction has one state.
+	base   []int
+	states int
+}
+
+// newProgram compiles a syntax tree to a program.
+func newProgram(expr *syntax.Node) *program {
+	p := &program{}
+	groups := make(map[*syntax.Node]int)
+	for i, group := range expr.Groups() {
+		groups[group] = i + 1
+	}
+	match := p.emit(inst{op: instMatch})
+	p.start = p.compile(expr, match, groups, expr.Alphabet())
+
+	for _, in := range p.insts {
+		p.base = append(p.base, p.states)
+		if in.op == instDfa {
+			p.states += in.d.Q
+		} else {
+			p.states++
+		}
+	}
+	return p
+}
+
+// emit appends an instruction to the program and returns its index.
+func (p *program) emit(in inst) int {
+	p.insts = append(p.insts, in)
+	return len(p.insts) - 1
+}
+
+// compile compiles the node so that a match of it continues at the
+// instruction next, and returns the index of its first instruction.
+// The alternatives of each instSplit instruction are ordered so that
+// earlier alternatives and more repetitions are preferred.
+func (p *program) compile(n *syntax.Node, next int, groups map[*syntax.Node]int, alphabet []rune) int {
+	switch n.Op {
+	case syntax.OpEmptyMatch:
+		return next
+	case syntax.OpLiteral:
+		return p.emit(inst{op: instRune, r: n.Rune, next: next})
+	case syntax.OpConcat:
+		for i := len(n.Sub) - 1; i >= 0; i-- {
+			next = p.compile(n.Sub[i], next, groups, alphabet)
+		}
+		return next
+	case syntax.OpAlternate:
+		if len(n.Sub) == 0 {
+			return p.emit(inst{op: instFail})
+		}
+		pc := p.compile(n.Sub[len(n.Sub)-1], next, groups, alphabet)
+		for i := len(n.Sub) - 2; i >= 0; i-- {
+			first := p.compile(n.Sub[i], next, groups, alphabet)
+			pc = p.emit(inst{op: instSplit, next: first, alt: pc})
+		}
+		return pc
+	case syntax.OpStar:
+		loop := p.emit(inst{op: instSplit, alt: next})
+		body := p.compile(n.Sub[0], loop, groups, alphabet)
+		p.insts[loop].next = body
+		return loop
+	case syntax.OpGroup:
+		i := groups[n]
+		end := p.emit(inst{op: instSave, slot: 2*i + 1, next: next})
+		body := p.compile(n.Sub[0], end, groups, alphabet)
+		return p.emit(inst{op: instSave, slot: 2 * i, next: body})
+	case syntax.OpIntersect, syntax.OpComplement:
+		d := derivativeDfaOver(n, alphabet)
+		return p.emit(inst{op: instDfa, d: d, next: next})
+	}
+	return p.emit(inst{op: instFail})
+}
+
+// thread is a point in the search for a match: an instruction, a
+// state of its Dfa for an instDfa instruction, and a position in the
+// string. A thread with restore set instead records that slot must be
+// reset to pos when the search backtracks past it.
+type thread struct {
+	pc      int
+	q       int
+	pos     int
+	restore bool
+	slot    int
+}
+
+// run searches for a way for the program to match s[start:],
+// recording the locations of the capture groups in loc. Alternatives
+// are tried in order of preference, so the locations are those of
+// the preferred match. The search never visits the same instruction,
+// Dfa state and position twice, since the first visit found no match
+// from there, so it takes time proportional to the length of the
+// match times the number of states of the program.
+func (p *program) run(s string, start int, loc []int) bool {
+	width := len(s) - start + 1
+	visited := make([]uint32, (p.states*width+31)/32)
+	stack := []thread{p.enter(p.start, start)}
+
+	for len(stack) > 0 {
+		t := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if t.restore {
+			loc[t.slot] = t.pos
+			continue
+		}
+
+		for {
+			v := (p.base[t.pc]+t.q)*width + t.pos - start
+			if visited[v/32]&(1<<uint(v%32)) != 0 {
+				break
+			}
+			visited[v/32] |= 1 << uint(v%32)
+
+			in := &p.insts[t.pc]
+			if in.op == instMatch {
+				if t.pos == len(s) {
+					return true
+				}
+				break
+			}
+			if in.op == instSplit {
+				stack = append(stack, p.enter(in.alt, t.pos))
+				t = p.enter(in.next, t.pos)
+				continue
+			}
+			if in.op == instSave {
+				stack = append(stack, thread{restore: true, slot: in.slot, pos: loc[in.slot]})
+				loc[in.slot] = t.pos
+				t = p.enter(in.next, t.pos)
+				continue
+			}
+			if in.op == instDfa && in.d.F.Contains(t.q) {
+				stack = append(stack, p.enter(in.next, t.pos))
+			}
+
+			letter, size := utf8.DecodeRuneInString(s[t.pos:])
+			if size == 0 {
+				break
+			}
+			if in.op == instRune && letter == in.r {
+				t = p.enter(in.next, t.pos+size)
+				continue
+			}
+			if in.op == instDfa {
+				if to, ok := in.d.D[t.q][letter]; ok {
+					t.q, t.pos = to, t.pos+size
+					continue
+				}
+			}
+			break
+		}
+	}
+	return false
+}
+
+// enter returns a thread starting the instruction pc at position pos.
+func (p *program) enter(pc, pos int) thread {
+	t := thread{pc: pc, pos: pos}
+	if in := &p.insts[pc]; in.op == instDfa {
+		t.q = in.d.Qs
+	}
+	return t
+}
@@ -0,0 +1,127 @@
+package regex_test
+
+import (
+	"github.com/paulgriffiths/automata/regex"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestFindStringSubmatch(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		input   string
+		want    []string
+	}{
+		{"a(b*)c", "xabbcx", []string{"abbc", "bb"}},
+		{"(a|ab)(c|bcd)", "abcd", []string{"abcd", "a", "bcd"}},
+		{"((a)|b)*", "ab", []string{"ab", "b", "a"}},
+		{"(a)|(b)", "b", []string{"b", "", "b"}},
+		{"(?P<year>1|2)(?P<rest>00*)", "x2000", []string{"2000", "2", "000"}},
+		{"a(~(b*))", "abab", []string{"abab", "bab", ""}},
+		{"(a)b", "xyz", nil},
+	}
+
+	for n, tc := range testCases {
+		r := regex.CompileWithOptions(tc.pattern, regex.Options{Backend: regex.Derivatives})
+		if got := r.FindStringSubmatch(tc.input); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("case %d, got %q, want %q", n+1, got, tc.want)
+		}
+	}
+}
+
+func TestFindStringSubmatchIndex(t *testing.T) {
+	r := regex.Compile("(a)|(b)")
+	if got, want := r.FindStringSubmatchIndex("cb"), []int{1, 2, -1, -1, 1, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	k := regex.CompileKeywords([]string{"ab"})
+	if got, want := k.FindStringSubmatchIndex("cab"), []int{1, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestSubmatchAmbiguous(t *testing.T) {
+	// Backtracking naively over these takes time exponential in the
+	// length of the input.
+	n := 10000
+	input := strings.Repeat("a", n)
+	testCases := []struct {
+		pattern string
+		want    []int
+	}{
+		{"(a|aa)*ab|(a|aa)*", []int{0, n, -1, -1, n - 1, n}},
+		{"((a*)*)*b|(a*)*", []int{0, n, -1, -1, -1, -1, 0, n}},
+		{"(a|a)*(a|a)*(a|a)*c|(a)", []int{0, 1, -1, -1, -1, -1, -1, -1, 0, 1}},
+		{"(~(b)|a)*", []int{0, n, 0, n, -1, -1}},
+	}
+
+	for i, tc := range testCases {
+		r := regex.CompileWithOptions(tc.pattern, regex.Options{Backend: regex.Derivatives})
+		if got := r.FindStringSubmatchIndex(input); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("case %d, got %v, want %v", i+1, got, tc.want)
+		}
+	}
+
+	r := regex.Compile("(a|aa)*ab|(a|aa)*")
+	if got := r.ReplaceAllString(input, "[$2]"); got != "[a]" {
+		t.Errorf("got %q, want %q", got, "[a]")
+	}
+}
+
+func TestSubexpNames(t *testing.T) {
+	r := regex.Compile("(?P<first>a)(b)(?P<last>c)")
+	if got, want := r.NumSubexp(), 3; got != want {
+		t.Errorf("got %d groups, want %d", got, want)
+	}
+	if got, want := r.SubexpNames(), []string{"", "first", "", "last"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got names %q, want %q", got, want)
+	}
+	for name, want := range map[string]int{"first": 1, "last": 3, "": -1, "middle": -1} {
+		if got := r.SubexpIndex(name); got != want {
+			t.Errorf("name %q, got index %d, want %d", name, got, want)
+		}
+	}
+	if got := r.String(); got != "(?P<first>a)(b)(?P<last>c)" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSubmatchFoldCase(t *testing.T) {
+	r := regex.CompileWithOptions("(?P<word>ab)c", regex.Options{FoldCase: true})
+	if got, want := r.ReplaceAllString("xABcx", "<$word>"), "x<AB>x"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSubmatchConcurrent(t *testing.T) {
+	r := regex.CompileWithOptions("(x)((a|b|c|d)*&~(abc))",
+		regex.Options{Backend: regex.Derivatives})
+	input := strings.Repeat("xabd", 50)
+	want := strings.Repeat("[abd]", 50)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := r.ReplaceAllString(input, "[$2]"); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkReplaceAllString(b *testing.B) {
+	r := regex.CompileWithOptions("(x)((a|b|c|d)*&~(abc))",
+		regex.Options{Backend: regex.Derivatives})
+	input := strings.Repeat("xabd", 2000)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.ReplaceAllString(input, "$2")
+	}
+}
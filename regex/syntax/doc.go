@@ -0,0 +1,18 @@
+/*
+Package syntax parses regular expressions into abstract syntax trees.
+
+The accepted syntax is that of package regex: letters and digits,
+concatenation, union (|), and Kleene star (*), with parentheses for
+grouping. A group may be named with the syntax (?P<name>expr). In
+addition, the intersection (&) and complement (~) operators are
+recognized. Intersection has a lower precedence than
+concatenation and a higher precedence than union. Complement is a
+prefix operator with the same precedence as the Kleene star, and
+denotes all strings over the expression's alphabet which are not
+matched by its operand.
+
+The String method of a syntax tree returns the regular expression it
+represents, and the Simplify method returns an equivalent, simplified
+syntax tree.
+*/
+package syntax
@@ -0,0 +1,21 @@
+package syntax_test
+
+import (
+	"fmt"
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"log"
+)
+
+func Example() {
+	node, err := syntax.Parse("(abc|abd)|((ab)e)")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(node)
+	fmt.Println(node.Simplify())
+
+	// Output:
+	// (abc|abd)|((ab)e)
+	// ab(c|d|e)
+}
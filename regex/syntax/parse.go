@@ -0,0 +1,176 @@
+package syntax
+
+import (
+	"errors"
+	"github.com/paulgriffiths/goeval/lar"
+	"strings"
+)
+
+// Errors returned by Parse.
+var (
+	ErrEmptyPattern      = errors.New("syntax: empty pattern")
+	ErrMissingOperand    = errors.New("syntax: missing operand")
+	ErrMissingParen      = errors.New("syntax: missing closing parenthesis")
+	ErrTrailingInput     = errors.New("syntax: unexpected input")
+	ErrUnreadablePattern = errors.New("syntax: couldn't read pattern")
+	ErrBadGroupName      = errors.New("syntax: invalid capture group name")
+	ErrDuplicateName     = errors.New("syntax: duplicate capture group name")
+)
+
+// Parse parses a regular expression and returns its syntax tree.
+func Parse(pattern string) (*Node, error) {
+	lar, err := lar.NewLookaheadReader(strings.NewReader(pattern))
+	if err != nil {
+		return nil, ErrUnreadablePattern
+	}
+
+	if lar.EndOfInput() {
+		return nil, ErrEmptyPattern
+	}
+
+	expr, err := getExpr(&lar)
+	if err != nil {
+		return nil, err
+	}
+	if expr == nil || !lar.EndOfInput() {
+		return nil, ErrTrailingInput
+	}
+
+	names := make(map[string]bool)
+	for _, group := range expr.Groups() {
+		if group.Name != "" && names[group.Name] {
+			return nil, ErrDuplicateName
+		}
+		names[group.Name] = true
+	}
+
+	return expr, nil
+}
+
+// getExpr parses a union of intersections. It returns nil and no
+// error if no expression begins at the current input position.
+func getExpr(lar *lar.LookaheadReader) (*Node, error) {
+	return getBinary(lar, '|', OpAlternate, getIntersect)
+}
+
+// getIntersect parses an intersection of concatenations.
+func getIntersect(lar *lar.LookaheadReader) (*Node, error) {
+	return getBinary(lar, '&', OpIntersect, getConcat)
+}
+
+// getBinary parses one or more operands separated by the operator
+// op, and returns a node of kind kind if more than one operand was
+// found.
+func getBinary(lar *lar.LookaheadReader, op rune, kind Op,
+	operand func(*lar.LookaheadReader) (*Node, error)) (*Node, error) {
+	first, err := operand(lar)
+	if first == nil || err != nil {
+		return nil, err
+	}
+
+	subs := []*Node{first}
+	for lar.MatchOneOf(op) {
+		next, err := operand(lar)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			return nil, ErrMissingOperand
+		}
+		subs = append(subs, next)
+	}
+
+	if len(subs) == 1 {
+		return first, nil
+	}
+	return &Node{Op: kind, Sub: subs}, nil
+}
+
+func getConcat(lar *lar.LookaheadReader) (*Node, error) {
+	subs := []*Node{}
+	for {
+		next, err := getClosure(lar)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			break
+		}
+		subs = append(subs, next)
+	}
+
+	switch len(subs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return subs[0], nil
+	}
+	return &Node{Op: OpConcat, Sub: subs}, nil
+}
+
+func getClosure(lar *lar.LookaheadReader) (*Node, error) {
+	if lar.MatchOneOf('~') {
+		operand, err := getClosure(lar)
+		if err != nil {
+			return nil, err
+		}
+		if operand == nil {
+			return nil, ErrMissingOperand
+		}
+		return &Node{Op: OpComplement, Sub: []*Node{operand}}, nil
+	}
+
+	term, err := getTerm(lar)
+	if term == nil || err != nil {
+		return nil, err
+	}
+	if lar.MatchOneOf('*') {
+		return &Node{Op: OpStar, Sub: []*Node{term}}, nil
+	}
+	return term, nil
+}
+
+func getTerm(lar *lar.LookaheadReader) (*Node, error) {
+	switch {
+	case lar.MatchLetter(), lar.MatchDigit():
+		return &Node{Op: OpLiteral, Rune: lar.Result.Value[0]}, nil
+	case lar.MatchOneOf('('):
+		name, err := getGroupName(lar)
+		if err != nil {
+			return nil, err
+		}
+		expr, err := getExpr(lar)
+		if err != nil {
+			return nil, err
+		}
+		if expr == nil {
+			return nil, ErrMissingOperand
+		}
+		if !lar.MatchOneOf(')') {
+			return nil, ErrMissingParen
+		}
+		return &Node{Op: OpGroup, Name: name, Sub: []*Node{expr}}, nil
+	}
+	return nil, nil
+}
+
+// getGroupName parses the ?P<name> which may follow the opening
+// parenthesis of a named capture group. It returns the empty string
+// and no error if the group is not named.
+func getGroupName(lar *lar.LookaheadReader) (string, error) {
+	if !lar.MatchOneOf('?') {
+		return "", nil
+	}
+	if !lar.MatchOneOf('P') || !lar.MatchOneOf('<') {
+		return "", ErrBadGroupName
+	}
+
+	name := []rune{}
+	for lar.MatchLetter() || lar.MatchDigit() || lar.MatchOneOf('_') {
+		name = append(name, lar.Result.Value[0])
+	}
+	if len(name) == 0 || !lar.MatchOneOf('>') {
+		return "", ErrBadGroupName
+	}
+	return string(name), nil
+}
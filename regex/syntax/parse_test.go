@@ -0,0 +1,206 @@
+package syntax_test
+
+import (
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		op      syntax.Op
+		subs    int
+	}{
+		{"a", syntax.OpLiteral, 0},
+		{"((a))", syntax.OpGroup, 1},
+		{"abc", syntax.OpConcat, 3},
+		{"a|b|c", syntax.OpAlternate, 3},
+		{"ab|c", syntax.OpAlternate, 2},
+		{"a*", syntax.OpStar, 1},
+		{"ab*", syntax.OpConcat, 2},
+		{"a&b|c", syntax.OpAlternate, 2},
+		{"ab&c", syntax.OpIntersect, 2},
+		{"~a", syntax.OpComplement, 1},
+		{"~ab", syntax.OpConcat, 2},
+		{"~a*", syntax.OpComplement, 1},
+		{"(?P<x1>ab)", syntax.OpGroup, 1},
+	}
+
+	for n, tc := range testCases {
+		node, err := syntax.Parse(tc.pattern)
+		if err != nil {
+			t.Errorf("case %d, couldn't parse %q: %v", n+1, tc.pattern, err)
+			continue
+		}
+		if node.Op != tc.op || len(node.Sub) != tc.subs {
+			t.Errorf("case %d, got (%d, %d), want (%d, %d)",
+				n+1, node.Op, len(node.Sub), tc.op, tc.subs)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		err     error
+	}{
+		{"", syntax.ErrEmptyPattern},
+		{"()", syntax.ErrMissingOperand},
+		{"(a", syntax.ErrMissingParen},
+		{"a(b", syntax.ErrMissingParen},
+		{"a|", syntax.ErrMissingOperand},
+		{"a&", syntax.ErrMissingOperand},
+		{"~", syntax.ErrMissingOperand},
+		{"a)", syntax.ErrTrailingInput},
+		{"*", syntax.ErrTrailingInput},
+		{"a**", syntax.ErrTrailingInput},
+		{"|a", syntax.ErrTrailingInput},
+		{"a+", syntax.ErrTrailingInput},
+		{"a?", syntax.ErrTrailingInput},
+		{"(?a)", syntax.ErrBadGroupName},
+		{"(?P<>a)", syntax.ErrBadGroupName},
+		{"(?P<x", syntax.ErrBadGroupName},
+		{"(?P<x-y>a)", syntax.ErrBadGroupName},
+		{"(?P<x>)", syntax.ErrMissingOperand},
+		{"(?P<x>a)(?P<x>b)", syntax.ErrDuplicateName},
+	}
+
+	for n, tc := range testCases {
+		if _, err := syntax.Parse(tc.pattern); err != tc.err {
+			t.Errorf("case %d, got %v, want %v", n+1, err, tc.err)
+		}
+	}
+}
+
+func TestAlphabet(t *testing.T) {
+	node, err := syntax.Parse("(ba|c)*ab")
+	if err != nil {
+		t.Fatalf("couldn't parse pattern: %v", err)
+	}
+	if got := string(node.Alphabet()); got != "bac" {
+		t.Errorf("got %q, want %q", got, "bac")
+	}
+}
+
+func TestGroups(t *testing.T) {
+	node, err := syntax.Parse("(?P<outer>a(b)(?P<inner>c))|(d)")
+	if err != nil {
+		t.Fatalf("couldn't parse pattern: %v", err)
+	}
+
+	want := []struct {
+		name string
+		expr string
+	}{
+		{"outer", "a(b)(?P<inner>c)"},
+		{"", "b"},
+		{"inner", "c"},
+		{"", "d"},
+	}
+	groups := node.Groups()
+	if len(groups) != len(want) {
+		t.Fatalf("got %d groups, want %d", len(groups), len(want))
+	}
+	for i, group := range groups {
+		if group.Name != want[i].name || group.Sub[0].String() != want[i].expr {
+			t.Errorf("group %d, got %q, %q, want %q, %q", i+1,
+				group.Name, group.Sub[0], want[i].name, want[i].expr)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	testCases := []string{
+		"a",
+		"((a))",
+		"ab*c",
+		"(ab)*c",
+		"a|bc|d*",
+		"(a|b)*abb",
+		"aa(a|b)*bb",
+		"((aa|bb)(aa|bb))*",
+		"ab&c|d",
+		"~a*b",
+		"~(a|b)",
+		"~~a",
+		"(?P<first>a)(?P<rest_2>b|(c))*",
+	}
+
+	for n, tc := range testCases {
+		node, err := syntax.Parse(tc)
+		if err != nil {
+			t.Errorf("case %d, couldn't parse %q: %v", n+1, tc, err)
+			continue
+		}
+		if s := node.String(); s != tc {
+			t.Errorf("case %d, got %q, want %q", n+1, s, tc)
+		}
+	}
+}
+
+func TestStringAddsParentheses(t *testing.T) {
+	a := &syntax.Node{Op: syntax.OpLiteral, Rune: 'a'}
+	b := &syntax.Node{Op: syntax.OpLiteral, Rune: 'b'}
+	alt := &syntax.Node{Op: syntax.OpAlternate, Sub: []*syntax.Node{a, b}}
+	cat := &syntax.Node{Op: syntax.OpConcat, Sub: []*syntax.Node{a, b}}
+	star := &syntax.Node{Op: syntax.OpStar, Sub: []*syntax.Node{a}}
+
+	testCases := []struct {
+		node *syntax.Node
+		want string
+	}{
+		{&syntax.Node{Op: syntax.OpConcat, Sub: []*syntax.Node{alt, b}},
+			"(a|b)b"},
+		{&syntax.Node{Op: syntax.OpStar, Sub: []*syntax.Node{cat}},
+			"(ab)*"},
+		{&syntax.Node{Op: syntax.OpStar, Sub: []*syntax.Node{star}},
+			"(a*)*"},
+		{&syntax.Node{Op: syntax.OpAlternate, Sub: []*syntax.Node{cat, star}},
+			"ab|a*"},
+		{&syntax.Node{Op: syntax.OpConcat, Sub: []*syntax.Node{alt}},
+			"a|b"},
+		{&syntax.Node{Op: syntax.OpStar, Sub: []*syntax.Node{
+			{Op: syntax.OpComplement, Sub: []*syntax.Node{a}}}},
+			"(~a)*"},
+	}
+
+	for n, tc := range testCases {
+		if s := tc.node.String(); s != tc.want {
+			t.Errorf("case %d, got %q, want %q", n+1, s, tc.want)
+		}
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	testCases := []struct {
+		pattern, want string
+	}{
+		{"((a))", "a"},
+		{"(ab)c", "abc"},
+		{"(a|b)|c", "a|b|c"},
+		{"a|b|a", "a|b"},
+		{"(a*)*", "a*"},
+		{"ab|ac", "a(b|c)"},
+		{"ac|bc", "(a|b)c"},
+		{"abc|abd|abe", "ab(c|d|e)"},
+		{"a&a", "a"},
+		{"~(~a)", "a"},
+		{"(a|b)*abb", "(a|b)*abb"},
+		{"ab|a*", "ab|a*"},
+	}
+
+	for n, tc := range testCases {
+		node, err := syntax.Parse(tc.pattern)
+		if err != nil {
+			t.Errorf("case %d, couldn't parse %q: %v", n+1, tc.pattern, err)
+			continue
+		}
+		if s := node.Simplify().String(); s != tc.want {
+			t.Errorf("case %d, got %q, want %q", n+1, s, tc.want)
+		}
+		if s := node.String(); s != tc.pattern {
+			t.Errorf("case %d, Simplify modified node, got %q, want %q",
+				n+1, s, tc.pattern)
+		}
+	}
+}
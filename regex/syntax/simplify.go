@@ -0,0 +1,296 @@
+package syntax
+
+// Simplify returns a simplified regular expression equivalent to
+// the node. Groups are removed, nested operators of the same kind
+// are flattened, repeated alternatives and intersected operands are
+// removed, redundant closures and double complements are collapsed,
+// empty strings and empty languages are eliminated where possible,
+// and common prefixes and suffixes of alternatives are factored out.
+// An empty string which cannot be eliminated is left as an
+// alternative of kind OpEmptyMatch.
+// The node itself is not modified.
+func (n *Node) Simplify() *Node {
+	switch n.Op {
+	case OpGroup:
+		return n.Sub[0].Simplify()
+	case OpConcat:
+		return newConcat(n.simplifySubs()...)
+	case OpAlternate:
+		return newAlternate(n.simplifySubs()...)
+	case OpIntersect:
+		return newIntersect(n.simplifySubs()...)
+	case OpStar:
+		return newStar(n.Sub[0].Simplify())
+	case OpComplement:
+		sub := n.Sub[0].Simplify()
+		if sub.Op == OpComplement {
+			return sub.Sub[0]
+		}
+		return &Node{Op: OpComplement, Sub: []*Node{sub}}
+	}
+	return &Node{Op: n.Op, Rune: n.Rune}
+}
+
+// simplifySubs returns the simplified subexpressions of the node.
+func (n *Node) simplifySubs() []*Node {
+	subs := make([]*Node, len(n.Sub))
+	for i, sub := range n.Sub {
+		subs[i] = sub.Simplify()
+	}
+	return subs
+}
+
+// newConcat returns the concatenation of simplified expressions.
+func newConcat(subs ...*Node) *Node {
+	result := []*Node{}
+	for _, sub := range subs {
+		switch sub.Op {
+		case OpNoMatch:
+			return &Node{Op: OpNoMatch}
+		case OpEmptyMatch:
+		case OpConcat:
+			result = append(result, sub.Sub...)
+		default:
+			result = append(result, sub)
+		}
+	}
+
+	// x(ε|y)z is equivalent to xz|xyz.
+	for i, sub := range result {
+		if len(result) > 1 && hasEmpty(sub) {
+			without := append(append([]*Node{}, result[:i]...), result[i+1:]...)
+			with := append(append([]*Node{}, result[:i]...), newAlternate(sub.Sub[1:]...))
+			with = append(with, result[i+1:]...)
+			return newAlternate(newConcat(without...), newConcat(with...))
+		}
+	}
+
+	switch len(result) {
+	case 0:
+		return &Node{Op: OpEmptyMatch}
+	case 1:
+		return result[0]
+	}
+	return &Node{Op: OpConcat, Sub: result}
+}
+
+// newAlternate returns the union of simplified expressions.
+func newAlternate(subs ...*Node) *Node {
+	result := []*Node{}
+	seen := make(map[string]bool)
+	empty, nullable := false, false
+	for _, sub := range flatten(OpAlternate, subs) {
+		switch {
+		case sub.Op == OpEmptyMatch:
+			empty = true
+		case sub.Op != OpNoMatch && !seen[sub.String()]:
+			seen[sub.String()] = true
+			result = append(result, sub)
+			nullable = nullable || sub.nullable()
+		}
+	}
+
+	// ε|xx* and ε|x*x are equivalent to x*. The alternatives of a
+	// concatenation beginning or ending with a union are also tried,
+	// so that ε|(a|b)b* becomes ab*|b*.
+	for i, sub := range result {
+		if nullable || !empty {
+			break
+		}
+		if star, ok := closureOf(sub); ok {
+			result[i], nullable = star, true
+			continue
+		}
+		for _, alt := range unfactor(sub) {
+			if _, ok := closureOf(alt); ok {
+				subs := append([]*Node{{Op: OpEmptyMatch}}, result[:i]...)
+				subs = append(append(subs, unfactor(sub)...), result[i+1:]...)
+				return newAlternate(subs...)
+			}
+		}
+	}
+	if empty && !nullable {
+		result = append([]*Node{{Op: OpEmptyMatch}}, result...)
+	}
+
+	switch len(result) {
+	case 0:
+		return &Node{Op: OpNoMatch}
+	case 1:
+		return result[0]
+	}
+
+	// Factoring is skipped if it would leave an empty string which
+	// cannot be eliminated, as in a(ε|b).
+	if prefix, rest, ok := factor(result, true); ok && !hasEmpty(newAlternate(rest...)) {
+		return newConcat(prefix, newAlternate(rest...))
+	}
+	if suffix, rest, ok := factor(result, false); ok && !hasEmpty(newAlternate(rest...)) {
+		return newConcat(newAlternate(rest...), suffix)
+	}
+
+	return &Node{Op: OpAlternate, Sub: result}
+}
+
+// newIntersect returns the intersection of simplified expressions.
+func newIntersect(subs ...*Node) *Node {
+	result := []*Node{}
+	seen := make(map[string]bool)
+	for _, sub := range flatten(OpIntersect, subs) {
+		if sub.Op == OpNoMatch {
+			return sub
+		}
+		if seen[sub.String()] {
+			continue
+		}
+		seen[sub.String()] = true
+		result = append(result, sub)
+	}
+
+	if len(result) == 1 {
+		return result[0]
+	}
+	return &Node{Op: OpIntersect, Sub: result}
+}
+
+// newStar returns the closure of a simplified expression.
+func newStar(sub *Node) *Node {
+	switch sub.Op {
+	case OpStar:
+		return sub
+	case OpNoMatch, OpEmptyMatch:
+		return &Node{Op: OpEmptyMatch}
+	case OpAlternate:
+		// (ε|x)* is equivalent to x*.
+		rest := []*Node{}
+		for _, alt := range sub.Sub {
+			if alt.Op != OpEmptyMatch {
+				rest = append(rest, alt)
+			}
+		}
+		if len(rest) != len(sub.Sub) {
+			return newStar(newAlternate(rest...))
+		}
+	}
+	return &Node{Op: OpStar, Sub: []*Node{sub}}
+}
+
+// closureOf checks if a simplified expression is of the form xx* or
+// x*x. If it is, it returns x*.
+func closureOf(n *Node) (*Node, bool) {
+	if n.Op != OpConcat {
+		return nil, false
+	}
+	first, last := n.Sub[0], n.Sub[len(n.Sub)-1]
+	if last.Op == OpStar && last.Sub[0].String() == newConcat(n.Sub[:len(n.Sub)-1]...).String() {
+		return last, true
+	}
+	if first.Op == OpStar && first.Sub[0].String() == newConcat(n.Sub[1:]...).String() {
+		return first, true
+	}
+	return nil, false
+}
+
+// unfactor returns the alternatives of a simplified concatenation
+// which begins or ends with a union, each concatenated with the rest
+// of it. Otherwise, it returns the node alone.
+func unfactor(n *Node) []*Node {
+	if n.Op != OpConcat {
+		return []*Node{n}
+	}
+	first, last := n.Sub[0], n.Sub[len(n.Sub)-1]
+	result := []*Node{}
+	switch {
+	case first.Op == OpAlternate:
+		for _, alt := range first.Sub {
+			result = append(result, newConcat(append([]*Node{alt}, n.Sub[1:]...)...))
+		}
+	case last.Op == OpAlternate:
+		for _, alt := range last.Sub {
+			result = append(result, newConcat(append(n.Sub[:len(n.Sub)-1:len(n.Sub)-1], alt)...))
+		}
+	default:
+		result = append(result, n)
+	}
+	return result
+}
+
+// hasEmpty returns true if a simplified expression is the empty
+// string, or a union with the empty string as an alternative.
+func hasEmpty(n *Node) bool {
+	return n.Op == OpEmptyMatch ||
+		n.Op == OpAlternate && n.Sub[0].Op == OpEmptyMatch
+}
+
+// nullable returns true if the node matches the empty string.
+func (n *Node) nullable() bool {
+	switch n.Op {
+	case OpEmptyMatch, OpStar:
+		return true
+	case OpConcat, OpIntersect:
+		for _, sub := range n.Sub {
+			if !sub.nullable() {
+				return false
+			}
+		}
+		return true
+	case OpAlternate:
+		for _, sub := range n.Sub {
+			if sub.nullable() {
+				return true
+			}
+		}
+		return false
+	case OpGroup:
+		return n.Sub[0].nullable()
+	case OpComplement:
+		return !n.Sub[0].nullable()
+	}
+	return false
+}
+
+// flatten returns the operands of an n-ary operator, replacing any
+// operand which is itself an application of the operator with its
+// own operands.
+func flatten(op Op, subs []*Node) []*Node {
+	result := []*Node{}
+	for _, sub := range subs {
+		if sub.Op == op {
+			result = append(result, flatten(op, sub.Sub)...)
+		} else {
+			result = append(result, sub)
+		}
+	}
+	return result
+}
+
+// factor checks if all the provided alternatives begin (or, if
+// prefix is false, end) with the same expression. If they do, it
+// returns that expression and the alternatives with it removed.
+func factor(alts []*Node, prefix bool) (*Node, []*Node, bool) {
+	end := func(n *Node) (*Node, *Node) {
+		if n.Op != OpConcat {
+			return n, &Node{Op: OpEmptyMatch}
+		}
+		if prefix {
+			return n.Sub[0], newConcat(n.Sub[1:]...)
+		}
+		last := len(n.Sub) - 1
+		return n.Sub[last], newConcat(n.Sub[:last]...)
+	}
+
+	common, _ := end(alts[0])
+	if common.Op == OpEmptyMatch {
+		return nil, nil, false
+	}
+
+	rest := make([]*Node, len(alts))
+	for i, alt := range alts {
+		e, r := end(alt)
+		if e.String() != common.String() {
+			return nil, nil, false
+		}
+		rest[i] = r
+	}
+	return common, rest, true
+}
@@ -0,0 +1,169 @@
+package syntax
+
+import "strings"
+
+// Op identifies the kind of a regular expression node.
+type Op int
+
+// Regular expression node kinds.
+const (
+	OpNoMatch    Op = iota // Matches no strings
+	OpEmptyMatch           // Matches only the empty string
+	OpLiteral              // Matches Rune
+	OpConcat               // Matches Sub[0], then Sub[1], and so on
+	OpAlternate            // Matches any of Sub
+	OpStar                 // Matches zero or more of Sub[0]
+	OpIntersect            // Matches strings matched by all of Sub
+	OpComplement           // Matches strings not matched by Sub[0]
+	OpGroup                // Matches Sub[0] and captures it, written in parentheses
+)
+
+// Node is a node in a regular expression syntax tree.
+type Node struct {
+	Op   Op      // Kind of node
+	Rune rune    // Matched rune, for OpLiteral
+	Name string  // Capture group name, for named OpGroup
+	Sub  []*Node // Subexpressions, if any
+}
+
+// Groups returns the capture groups in the expression, in the order
+// of their opening parentheses, so that group i, counting from 1, is
+// element i-1.
+func (n *Node) Groups() []*Node {
+	groups := []*Node{}
+	n.walk(func(m *Node) {
+		if m.Op == OpGroup {
+			groups = append(groups, m)
+		}
+	})
+	return groups
+}
+
+// Alphabet returns the runes which appear as literals in the
+// expression, in the order in which they first appear.
+func (n *Node) Alphabet() []rune {
+	seen := make(map[rune]bool)
+	alphabet := []rune{}
+	n.walk(func(m *Node) {
+		if m.Op == OpLiteral && !seen[m.Rune] {
+			seen[m.Rune] = true
+			alphabet = append(alphabet, m.Rune)
+		}
+	})
+	return alphabet
+}
+
+// walk calls f for n and every node beneath it, in prefix order.
+func (n *Node) walk(f func(*Node)) {
+	f(n)
+	for _, sub := range n.Sub {
+		sub.walk(f)
+	}
+}
+
+// Operator precedence levels, from loosest to tightest binding.
+const (
+	precAlternate = iota
+	precIntersect
+	precConcat
+	precClosure
+	precAtom
+)
+
+// String returns the regular expression represented by the node.
+// For trees returned by Parse, the result is the original pattern.
+// Parentheses are added to other trees only where operator
+// precedence requires them. Nodes of kind OpNoMatch and OpEmptyMatch
+// have no representation in the syntax accepted by Parse, and are
+// written as "∅" and "ε" respectively.
+func (n *Node) String() string {
+	var b strings.Builder
+	n.write(&b)
+	return b.String()
+}
+
+// write writes the regular expression represented by the node to
+// the provided builder.
+func (n *Node) write(b *strings.Builder) {
+	switch n.Op {
+	case OpNoMatch:
+		b.WriteString("∅")
+	case OpEmptyMatch:
+		b.WriteString("ε")
+	case OpLiteral:
+		b.WriteRune(n.Rune)
+	case OpConcat, OpAlternate, OpIntersect:
+		switch len(n.Sub) {
+		case 0:
+			b.WriteString(map[Op]string{
+				OpConcat:    "ε",
+				OpAlternate: "∅",
+				OpIntersect: "~∅",
+			}[n.Op])
+			return
+		case 1:
+			n.Sub[0].write(b)
+			return
+		}
+		for i, sub := range n.Sub {
+			if i > 0 && n.Op == OpAlternate {
+				b.WriteRune('|')
+			} else if i > 0 && n.Op == OpIntersect {
+				b.WriteRune('&')
+			}
+			sub.writeOperand(b, n.precedence()+1)
+		}
+	case OpStar:
+		n.Sub[0].writeOperand(b, precAtom)
+		b.WriteRune('*')
+	case OpComplement:
+		b.WriteRune('~')
+		n.Sub[0].writeOperand(b, precClosure)
+	case OpGroup:
+		b.WriteRune('(')
+		if n.Name != "" {
+			b.WriteString("?P<" + n.Name + ">")
+		}
+		n.Sub[0].write(b)
+		b.WriteRune(')')
+	}
+}
+
+// writeOperand writes the node as an operand of an operator with
+// the specified precedence, enclosing it in parentheses if its own
+// precedence is lower.
+func (n *Node) writeOperand(b *strings.Builder, prec int) {
+	if n.precedence() < prec {
+		b.WriteRune('(')
+		n.write(b)
+		b.WriteRune(')')
+		return
+	}
+	n.write(b)
+}
+
+// precedence returns the precedence level of the node's operator.
+// Concatenations, unions and intersections of a single operand take
+// the precedence of that operand, and those of no operands are
+// written as atoms.
+func (n *Node) precedence() int {
+	switch n.Op {
+	case OpAlternate, OpIntersect, OpConcat:
+		switch {
+		case len(n.Sub) == 0 && n.Op == OpIntersect:
+			return precClosure
+		case len(n.Sub) == 0:
+			return precAtom
+		case len(n.Sub) == 1:
+			return n.Sub[0].precedence()
+		}
+		return map[Op]int{
+			OpAlternate: precAlternate,
+			OpIntersect: precIntersect,
+			OpConcat:    precConcat,
+		}[n.Op]
+	case OpStar, OpComplement:
+		return precClosure
+	}
+	return precAtom
+}
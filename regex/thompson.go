@@ -0,0 +1,61 @@
+package regex
+
+import (
+	"github.com/paulgriffiths/automata/nfa"
+	"github.com/paulgriffiths/automata/regex/syntax"
+	"github.com/paulgriffiths/gods/sets"
+)
+
+// thompson builds a nondeterministic finite automaton from a regular
+// expression syntax tree using the McNaughton-Yamada-Thompson
+// algorithm. It returns false if the expression contains operators
+// which the algorithm does not support.
+func thompson(expr *syntax.Node) (nfa.Nfa, bool) {
+	switch expr.Op {
+	case syntax.OpNoMatch:
+		return nfa.Nfa{
+			Q:  2,
+			S:  sets.NewSetRune(),
+			D:  []map[rune]sets.SetInt{{}, {}},
+			Qs: 0,
+			F:  sets.NewSetInt(1),
+		}, true
+	case syntax.OpEmptyMatch:
+		return nfa.Nfa{
+			Q:  1,
+			S:  sets.NewSetRune(),
+			D:  []map[rune]sets.SetInt{{}},
+			Qs: 0,
+			F:  sets.NewSetInt(0),
+		}, true
+	case syntax.OpLiteral:
+		return nfa.NewRuneNfa(expr.Rune), true
+	case syntax.OpConcat, syntax.OpAlternate:
+		combine := nfa.NewConcatNfa
+		if expr.Op == syntax.OpAlternate {
+			combine = nfa.NewUnionNfa
+		}
+		result, ok := thompson(expr.Sub[0])
+		if !ok {
+			return nfa.Nfa{}, false
+		}
+		for _, sub := range expr.Sub[1:] {
+			next, ok := thompson(sub)
+			if !ok {
+				return nfa.Nfa{}, false
+			}
+			result = combine(result, next)
+		}
+		return result, true
+	case syntax.OpGroup:
+		return thompson(expr.Sub[0])
+	case syntax.OpStar:
+		sub, ok := thompson(expr.Sub[0])
+		if !ok {
+			return nfa.Nfa{}, false
+		}
+		return nfa.NewClosureNfa(sub), true
+	}
+
+	return nfa.Nfa{}, false
+}